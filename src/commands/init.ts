@@ -6,6 +6,7 @@ import pc from "picocolors";
 import { packageRoot } from "../config.js";
 import { supportedClients } from "../lib/clients.js";
 import { installBoth } from "../lib/install.js";
+import { snapshotClientConfig } from "../lib/mcpInstall.js";
 
 interface InitOptions {
   client?: string;
@@ -56,6 +57,16 @@ function printBanner(): void {
   );
 }
 
+/**
+ * Spinner stand-in for non-interactive output: one plain line per step
+ */
+function plainProgress(): Pick<ReturnType<typeof p.spinner>, "start" | "stop"> {
+  return {
+    start: (msg = "") => console.log(msg),
+    stop: (msg = "") => console.log(msg),
+  };
+}
+
 export function createInitCommand(): Command {
   const init = new Command("init")
     .description("Configure IDEs with MCP servers")
@@ -77,10 +88,23 @@ export function createInitCommand(): Command {
         }
       }
 
-      printBanner();
+      // Spinners and prompts redraw lines with escape codes, which garble
+      // piped or CI output, so fall back to plain lines there
+      const interactive = Boolean(process.stdout.isTTY && process.stdin.isTTY);
+
+      if (interactive) {
+        printBanner();
+      }
 
       let clientName = options.client;
 
+      if (!clientName && !interactive) {
+        console.error(
+          `Error: --client is required when not running in a terminal (one of: ${supportedClients.map((c) => c.name).join(", ")})`,
+        );
+        process.exit(1);
+      }
+
       // If no client specified, prompt interactively
       if (!clientName) {
         const selected = await p.select({
@@ -101,19 +125,35 @@ export function createInitCommand(): Command {
 
       const client = supportedClients.find((c) => c.name === clientName);
       if (!client) {
-        p.log.error(`Unknown client: ${clientName}`);
+        if (interactive) {
+          p.log.error(`Unknown client: ${clientName}`);
+        } else {
+          console.error(`Error: Unknown client: ${clientName}`);
+        }
         process.exit(1);
       }
 
-      const s = p.spinner();
+      const s = interactive ? p.spinner() : plainProgress();
       s.start(`Configuring ${client.displayName}...`);
 
+      // On Ctrl-C, kill the running install command and put the IDE config
+      // back the way it was instead of leaving it half-written
+      const controller = new AbortController();
+      const restoreConfig = snapshotClientConfig(clientName);
+      const onInterrupt = () => controller.abort();
+      process.once("SIGINT", onInterrupt);
+
       try {
         await installBoth(clientName, {
           devMode: options.dev,
           latest: options.latest,
+          signal: controller.signal,
         });
         s.stop(`${client.displayName} configured`);
+        if (!interactive) {
+          console.log("Done! Restart your IDE to use the MCP servers.");
+          return;
+        }
         p.outro("Done! Restart your IDE to use the MCP servers.");
         console.log("");
         console.log("Try asking your AI coding assistant:");
@@ -122,10 +162,27 @@ export function createInitCommand(): Command {
         console.log("  • Create a dashboard to track my fitness goals");
         console.log("");
       } catch (err) {
+        if (controller.signal.aborted) {
+          restoreConfig();
+          s.stop(`${client.displayName} not configured`);
+          if (interactive) {
+            p.cancel("Setup aborted. Your IDE config was left unchanged.");
+          } else {
+            console.error("Setup aborted. Your IDE config was left unchanged.");
+          }
+          process.exit(130);
+        }
+
         const error = err as Error;
         s.stop(`${client.displayName} failed`);
-        p.log.error(error.message);
+        if (interactive) {
+          p.log.error(error.message);
+        } else {
+          console.error(`Error: ${error.message}`);
+        }
         process.exit(1);
+      } finally {
+        process.off("SIGINT", onInterrupt);
       }
     });
 
@@ -1,5 +1,24 @@
 import { Command } from "commander";
 import { startMcpServer } from "../mcp/server.js";
+import {
+  describeTools,
+  getToolPrefix,
+  getToolProfile,
+  toolProfiles,
+} from "../mcp/tools/index.js";
+
+interface StartOptions {
+  toolProfile?: string;
+  toolPrefix?: string;
+}
+
+interface ToolsOptions extends StartOptions {
+  json: boolean;
+}
+
+const toolProfileHelp = `Tools to register (${toolProfiles.join(", ")}); defaults to $OPERATOR_TOOL_PROFILE or minimal`;
+const toolPrefixHelp =
+  "Prefix for every tool name (e.g. 0p_); defaults to $OPERATOR_TOOL_PREFIX or none";
 
 export function createMcpCommand(): Command {
   const mcp = new Command("mcp").description("MCP server commands");
@@ -7,8 +26,37 @@ export function createMcpCommand(): Command {
   mcp
     .command("start")
     .description("Start the MCP server")
-    .action(async () => {
-      await startMcpServer();
+    .option("--tool-profile <profile>", toolProfileHelp)
+    .option("--tool-prefix <prefix>", toolPrefixHelp)
+    .action(async (options: StartOptions) => {
+      await startMcpServer({
+        profile: getToolProfile(options.toolProfile),
+        prefix: getToolPrefix(options.toolPrefix),
+      });
+    });
+
+  mcp
+    .command("tools")
+    .description("List the tools the MCP server registers")
+    .option("--tool-profile <profile>", toolProfileHelp)
+    .option("--tool-prefix <prefix>", toolPrefixHelp)
+    .option("--json", "Print names, descriptions, and inputs as JSON", false)
+    .action(async (options: ToolsOptions) => {
+      const tools = await describeTools({
+        profile: getToolProfile(options.toolProfile),
+        prefix: getToolPrefix(options.toolPrefix),
+      });
+
+      if (options.json) {
+        console.log(JSON.stringify(tools, null, 2));
+        return;
+      }
+
+      const width = Math.max(...tools.map((t) => t.name.length));
+      for (const tool of tools) {
+        console.log(`${tool.name.padEnd(width)}  ${tool.title ?? ""}`);
+      }
+      console.log(`\n${tools.length} tools`);
     });
 
   return mcp;
@@ -0,0 +1,76 @@
+import { existsSync } from "node:fs";
+import { Command } from "commander";
+import pc from "picocolors";
+import { supportedClients } from "../lib/clients.js";
+import { findOnPath } from "../lib/exec.js";
+import {
+  findClientConfig,
+  findClientConfigFile,
+  getMCPServerConfig,
+} from "../lib/mcpInstall.js";
+import { getTigerAuthStatus, type TigerAuthStatus } from "../lib/tiger.js";
+
+const ok = pc.green("✓");
+const fail = pc.red("✗");
+
+const tigerMessages = {
+  authenticated: `${ok} tiger CLI installed and logged in`,
+  not_authenticated: `${fail} tiger CLI not logged in (run 'tiger auth login')`,
+  not_installed: `${fail} tiger CLI not found on PATH (run '0perator init')`,
+} as const;
+
+function describeTiger(status: TigerAuthStatus): string {
+  return status.state === "error"
+    ? `${fail} couldn't check the tiger CLI login: ${status.message}`
+    : tigerMessages[status.state];
+}
+
+/**
+ * Describe one client's 0perator entry, and whether its command still exists
+ */
+function describeClient(clientName: string): string {
+  const clientCfg = findClientConfig(clientName);
+  // findClientConfigFile falls back to the default path when none exists
+  const configPath = clientCfg && findClientConfigFile(clientCfg.configPaths);
+  if (!clientCfg || !configPath || !existsSync(configPath)) {
+    return `${pc.dim("-")} no config file`;
+  }
+
+  let entry: ReturnType<typeof getMCPServerConfig>;
+  try {
+    entry = getMCPServerConfig(clientCfg, configPath, "0perator");
+  } catch (err) {
+    return `${fail} ${(err as Error).message}`;
+  }
+  if (!entry) {
+    return `${pc.dim("-")} not configured`;
+  }
+  if (entry.command && !findOnPath(entry.command)) {
+    return `${fail} configured, but command '${entry.command}' was not found`;
+  }
+  return `${ok} configured (${configPath})`;
+}
+
+export function createStatusCommand(): Command {
+  return new Command("status")
+    .description("Check the tiger CLI login and which IDEs have 0perator")
+    .action(async () => {
+      const tiger = await getTigerAuthStatus();
+      console.log(describeTiger(tiger));
+      console.log();
+
+      const width = Math.max(
+        ...supportedClients.map((c) => c.displayName.length),
+      );
+      for (const client of supportedClients) {
+        console.log(
+          `${client.displayName.padEnd(width)}  ${describeClient(client.name)}`,
+        );
+      }
+
+      // Nothing works without Tiger Cloud access, so make that scriptable
+      if (tiger.state !== "authenticated") {
+        process.exit(1);
+      }
+    });
+}
@@ -0,0 +1,41 @@
+import { Command } from "commander";
+import { userTemplatesDir } from "../config.js";
+import { ejectTemplate, listTemplateFiles } from "../lib/templates.js";
+
+interface EjectOptions {
+  force: boolean;
+}
+
+export function createTemplatesCommand(): Command {
+  const templates = new Command("templates").description(
+    `Customize generated files (overrides live in ${userTemplatesDir})`,
+  );
+
+  templates
+    .command("list")
+    .description("List built-in templates and which ones are overridden")
+    .action(async () => {
+      for (const file of await listTemplateFiles()) {
+        console.log(`${file.path}${file.overridden ? "  (overridden)" : ""}`);
+      }
+    });
+
+  templates
+    .command("eject")
+    .description("Copy a built-in template to the overrides directory to edit")
+    .argument("<path>", "Template path from 'templates list'")
+    .option("--force", "Overwrite an existing override", false)
+    .action(async (path: string, options: EjectOptions) => {
+      try {
+        const dest = await ejectTemplate(path, options.force);
+        console.log(`Ejected ${path} to ${dest}`);
+        console.log("Edit it there; new projects will use your version.");
+      } catch (err) {
+        const error = err as Error;
+        console.error(`Error: ${error.message}`);
+        process.exit(1);
+      }
+    });
+
+  return templates;
+}
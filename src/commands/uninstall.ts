@@ -0,0 +1,95 @@
+import * as p from "@clack/prompts";
+import { Command } from "commander";
+import pc from "picocolors";
+import { supportedClients } from "../lib/clients.js";
+import { uninstallMCPForClient } from "../lib/mcpInstall.js";
+
+interface UninstallOptions {
+  client?: string[];
+  all: boolean;
+  tiger: boolean;
+}
+
+export function createUninstallCommand(): Command {
+  const uninstall = new Command("uninstall")
+    .description("Remove the 0perator MCP server from IDE configs")
+    .option(
+      "--client <name...>",
+      "Clients to clean up (default: prompt; required when not in a terminal unless --all is set)",
+    )
+    .option("--all", "Clean up every supported client", false)
+    .option("--tiger", "Also remove the Tiger MCP server", false)
+    .action(async (options: UninstallOptions) => {
+      if (options.all && options.client) {
+        console.error("Error: Pass either --client or --all, not both");
+        process.exit(1);
+      }
+
+      let clientNames = options.all
+        ? supportedClients.map((c) => c.name)
+        : options.client;
+
+      const unknown = clientNames?.filter(
+        (name) => !supportedClients.some((c) => c.name === name),
+      );
+      if (unknown && unknown.length > 0) {
+        console.error(`Error: Unknown client: ${unknown.join(", ")}`);
+        process.exit(1);
+      }
+
+      if (!clientNames) {
+        // Removing from every client is destructive, so scripts must ask
+        // for it explicitly
+        if (!process.stdout.isTTY || !process.stdin.isTTY) {
+          console.error(
+            `Error: --client or --all is required when not running in a terminal (clients: ${supportedClients.map((c) => c.name).join(", ")})`,
+          );
+          process.exit(1);
+        }
+
+        const selected = await p.multiselect({
+          message: "Select IDEs to remove 0perator from",
+          options: supportedClients.map((c) => ({
+            label: c.displayName,
+            value: c.name,
+          })),
+        });
+        if (p.isCancel(selected)) {
+          p.cancel("Uninstall cancelled.");
+          process.exit(0);
+        }
+        clientNames = selected;
+      }
+
+      const serverNames = options.tiger ? ["0perator", "tiger"] : ["0perator"];
+      let failed = false;
+
+      for (const clientName of clientNames) {
+        const displayName =
+          supportedClients.find((c) => c.name === clientName)?.displayName ??
+          clientName;
+        for (const serverName of serverNames) {
+          try {
+            const removed = await uninstallMCPForClient(clientName, serverName);
+            console.log(
+              removed
+                ? `${pc.green("✓")} Removed ${serverName} from ${displayName}`
+                : `${pc.dim("-")} No ${serverName} entry in ${displayName}`,
+            );
+          } catch (err) {
+            const error = err as Error;
+            failed = true;
+            console.log(
+              `${pc.red("✗")} Failed to remove ${serverName} from ${displayName}: ${error.message}`,
+            );
+          }
+        }
+      }
+
+      if (failed) {
+        process.exit(1);
+      }
+    });
+
+  return uninstall;
+}
@@ -1,4 +1,5 @@
 import { readFileSync } from "node:fs";
+import { homedir } from "node:os";
 import { dirname, join } from "node:path";
 import { fileURLToPath } from "node:url";
 
@@ -13,6 +14,15 @@ export const skillsDir = join(packageRoot, "skills");
 // Templates directory at package root level
 export const templatesDir = join(packageRoot, "templates");
 
+// User template overrides: a file here replaces the built-in template at the
+// same relative path (e.g. app/biome.jsonc)
+export const userTemplatesDir =
+  process.env.OPERATOR_TEMPLATES_DIR ||
+  join(homedir(), ".0perator", "templates");
+
+// Tool call traces written when OPERATOR_TRACE is set
+export const tracesDir = join(homedir(), ".0perator", "traces");
+
 // Read version from package.json
 const pkg = JSON.parse(
   readFileSync(join(packageRoot, "package.json"), "utf-8"),
@@ -2,6 +2,9 @@
 import { Command } from "commander";
 import { createInitCommand } from "./commands/init.js";
 import { createMcpCommand } from "./commands/mcp.js";
+import { createStatusCommand } from "./commands/status.js";
+import { createTemplatesCommand } from "./commands/templates.js";
+import { createUninstallCommand } from "./commands/uninstall.js";
 import { version } from "./config.js";
 
 const program = new Command();
@@ -9,9 +12,21 @@ const program = new Command();
 program
   .name("0perator")
   .description("Infrastructure for AI native development")
-  .version(version);
+  .version(version)
+  .option(
+    "--debug",
+    "Log subprocess commands and their output to stderr (same as OPERATOR_DEBUG=1)",
+  )
+  .hook("preAction", (thisCommand) => {
+    if (thisCommand.opts().debug) {
+      process.env.OPERATOR_DEBUG = "1";
+    }
+  });
 
 program.addCommand(createInitCommand());
 program.addCommand(createMcpCommand());
+program.addCommand(createStatusCommand());
+program.addCommand(createTemplatesCommand());
+program.addCommand(createUninstallCommand());
 
 program.parse();
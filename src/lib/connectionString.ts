@@ -0,0 +1,56 @@
+const defaultPort = 5432;
+
+export interface ConnectionParams {
+  host: string;
+  port: number;
+  user: string;
+  password: string;
+  database: string;
+  // sslmode query parameter, e.g. "require" (omitted when not set)
+  sslmode?: string | undefined;
+}
+
+/**
+ * Split a postgres:// or postgresql:// URL into its parts, decoding the
+ * user and password
+ */
+export function parseConnectionString(
+  connectionString: string,
+): ConnectionParams {
+  let url: URL;
+  try {
+    url = new URL(connectionString);
+  } catch {
+    throw new Error("Invalid connection string: not a URL");
+  }
+
+  if (url.protocol !== "postgres:" && url.protocol !== "postgresql:") {
+    throw new Error(
+      `Invalid connection string: expected postgres:// or postgresql://, got ${url.protocol}//`,
+    );
+  }
+
+  return {
+    host: url.hostname,
+    port: url.port ? Number(url.port) : defaultPort,
+    user: decodeURIComponent(url.username),
+    password: decodeURIComponent(url.password),
+    database: decodeURIComponent(url.pathname.replace(/^\//, "")),
+    sslmode: url.searchParams.get("sslmode") ?? undefined,
+  };
+}
+
+/**
+ * Replace the user and password in a connection string, keeping the host,
+ * database, and any query parameters
+ */
+export function withCredentials(
+  connectionString: string,
+  user: string,
+  password: string,
+): string {
+  const url = new URL(connectionString);
+  url.username = encodeURIComponent(user);
+  url.password = encodeURIComponent(password);
+  return url.toString();
+}
@@ -0,0 +1,77 @@
+import { resolve } from "node:path";
+import { z } from "zod";
+import { parseConnectionString } from "./connectionString.js";
+import { envFileCandidates, findEnvValue } from "./env.js";
+
+// Inputs shared by tools that connect with the app's DATABASE_URL
+export const databaseUrlInputs = {
+  application_directory: z
+    .string()
+    .default(".")
+    .describe("Path to the application directory whose env has DATABASE_URL"),
+  env_file: z
+    .string()
+    .optional()
+    .describe(
+      `Env file to read DATABASE_URL from. By default the first of ${envFileCandidates.join(", ")} that sets it`,
+    ),
+  database_url: z
+    .string()
+    .optional()
+    .describe(
+      "Connection string to use. Overrides DATABASE_URL from the application's env files",
+    ),
+} as const;
+
+export type DatabaseUrlResult =
+  | { databaseUrl: string; envFile?: string | undefined }
+  | { error: string };
+
+/**
+ * Error for a connection string postgres.js can't use, or null if it's valid
+ */
+function connectionStringError(
+  databaseUrl: string,
+  source: string,
+): string | null {
+  try {
+    parseConnectionString(databaseUrl);
+    return null;
+  } catch (err) {
+    return `${(err as Error).message} (from ${source})`;
+  }
+}
+
+/**
+ * Resolve the connection string from an explicit database_url or the app's
+ * env files, in envFileCandidates priority order. Malformed URLs are
+ * reported here rather than as a connection failure.
+ */
+export async function resolveDatabaseUrl(options: {
+  application_directory: string;
+  env_file?: string | undefined;
+  database_url?: string | undefined;
+}): Promise<DatabaseUrlResult> {
+  if (options.database_url) {
+    const error = connectionStringError(options.database_url, "database_url");
+    return error ? { error } : { databaseUrl: options.database_url };
+  }
+
+  const appDir = resolve(process.cwd(), options.application_directory);
+  const found = await findEnvValue(
+    appDir,
+    "DATABASE_URL",
+    options.env_file ? [options.env_file] : envFileCandidates,
+  );
+  if (!found) {
+    return {
+      error: `No database_url given and DATABASE_URL is not set in ${options.env_file ?? envFileCandidates.join(", ")} under ${appDir}`,
+    };
+  }
+
+  const error = connectionStringError(
+    found.value,
+    `DATABASE_URL in ${found.file}`,
+  );
+  return error ? { error } : { databaseUrl: found.value, envFile: found.file };
+}
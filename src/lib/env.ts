@@ -0,0 +1,63 @@
+import { existsSync } from "node:fs";
+import { readFile, writeFile } from "node:fs/promises";
+import { join } from "node:path";
+import * as dotenv from "dotenv";
+
+// Env files checked for a variable, highest priority first
+export const envFileCandidates = [
+  ".env.local",
+  ".env.development.local",
+  ".env",
+  ".env.development",
+] as const;
+
+export interface EnvLookupResult {
+  value: string;
+  file: string;
+}
+
+/**
+ * Read and parse an env file, returning an empty object if it doesn't exist
+ */
+export async function readEnvFile(
+  envPath: string,
+): Promise<Record<string, string>> {
+  if (!existsSync(envPath)) {
+    return {};
+  }
+  return dotenv.parse(await readFile(envPath, "utf-8"));
+}
+
+/**
+ * Find a variable in the first env file (by priority) that defines it
+ */
+export async function findEnvValue(
+  appDir: string,
+  key: string,
+  files: readonly string[] = envFileCandidates,
+): Promise<EnvLookupResult | null> {
+  for (const file of files) {
+    const env = await readEnvFile(join(appDir, file));
+    const value = env[key];
+    if (value) {
+      return { value, file };
+    }
+  }
+  return null;
+}
+
+/**
+ * Set variables in an env file, keeping any other variables already there
+ */
+export async function updateEnvFile(
+  envPath: string,
+  vars: Record<string, string>,
+): Promise<void> {
+  const env = { ...(await readEnvFile(envPath)), ...vars };
+
+  const content = Object.entries(env)
+    .map(([key, value]) => `${key}="${value}"`)
+    .join("\n");
+
+  await writeFile(envPath, `${content}\n`);
+}
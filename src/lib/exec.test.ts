@@ -0,0 +1,77 @@
+import { mkdirSync, rmSync, writeFileSync } from "node:fs";
+import { tmpdir } from "node:os";
+import { join } from "node:path";
+import { afterEach, beforeEach, describe, expect, it, vi } from "vitest";
+import { resolveInvocation } from "./exec.js";
+
+const platform = Object.getOwnPropertyDescriptor(process, "platform");
+
+function setPlatform(value: NodeJS.Platform): void {
+  Object.defineProperty(process, "platform", { value });
+}
+
+describe("resolveInvocation", () => {
+  let binDir: string;
+
+  beforeEach(() => {
+    binDir = join(
+      tmpdir(),
+      `exec-test-${Date.now()}-${Math.random().toString(36).slice(2)}`,
+    );
+    mkdirSync(binDir, { recursive: true });
+    // npm installs both a sh script and a .cmd shim on Windows
+    writeFileSync(join(binDir, "npx"), "");
+    writeFileSync(join(binDir, "npx.cmd"), "");
+    vi.stubEnv("PATH", binDir);
+    vi.stubEnv("PATHEXT", ".EXE;.CMD");
+  });
+
+  afterEach(() => {
+    vi.unstubAllEnvs();
+    if (platform) {
+      Object.defineProperty(process, "platform", platform);
+    }
+    rmSync(binDir, { recursive: true, force: true });
+  });
+
+  it("should run binaries directly outside Windows", () => {
+    setPlatform("linux");
+
+    expect(resolveInvocation("npx", ["create-t3-app@latest"])).toEqual({
+      file: "npx",
+      args: ["create-t3-app@latest"],
+      shell: false,
+    });
+  });
+
+  it("should run .cmd shims through a shell with quoted arguments", () => {
+    setPlatform("win32");
+
+    expect(resolveInvocation("npx", ["my app", 'say "hi"'])).toEqual({
+      file: join(binDir, "npx.cmd"),
+      args: ['^^^"my^^^ app^^^"', '^^^"say^^^ \\^^^"hi\\^^^"^^^"'],
+      shell: true,
+    });
+  });
+
+  it("should escape % so cmd.exe doesn't expand variables", () => {
+    setPlatform("win32");
+
+    expect(resolveInvocation("npx", ["%PATH%", "100%"]).args).toEqual([
+      '^^^"^^^%PATH^^^%^^^"',
+      '^^^"100^^^%^^^"',
+    ]);
+  });
+
+  it("should escape ^ so cmd.exe keeps it", () => {
+    setPlatform("win32");
+
+    expect(resolveInvocation("npx", ["a^b"]).args).toEqual(['^^^"a^^^^b^^^"']);
+  });
+
+  it("should leave commands it can't find alone", () => {
+    setPlatform("win32");
+
+    expect(resolveInvocation("tiger", ["auth", "status"]).shell).toBe(false);
+  });
+});
@@ -0,0 +1,229 @@
+import { type ExecOptions, exec, execFile } from "node:child_process";
+import { existsSync } from "node:fs";
+import { delimiter, join } from "node:path";
+import { promisify } from "node:util";
+
+const execPromise = promisify(exec);
+const execFilePromise = promisify(execFile);
+
+export interface ExecOutput {
+  stdout: string;
+  stderr: string;
+}
+
+/**
+ * Whether OPERATOR_DEBUG is set, which logs every subprocess to stderr
+ */
+export function isDebugEnabled(): boolean {
+  const value = process.env.OPERATOR_DEBUG?.toLowerCase();
+  return value === "1" || value === "true";
+}
+
+/**
+ * Log a debug line to stderr (stdout is reserved for the MCP protocol)
+ */
+export function debugLog(message: string): void {
+  if (isDebugEnabled()) {
+    console.error(`[0perator debug] ${message}`);
+  }
+}
+
+function logStart(command: string, options: ExecOptions): void {
+  const cwd = options.cwd ? ` (cwd: ${options.cwd.toString()})` : "";
+  debugLog(`$ ${command}${cwd}`);
+}
+
+function logFailure(command: string, err: unknown): void {
+  const error = err as Error & {
+    code?: number | string;
+    stdout?: string;
+    stderr?: string;
+  };
+  debugLog(
+    `exit ${error.code ?? "unknown"}: ${command}\n--- stdout ---\n${error.stdout ?? ""}\n--- stderr ---\n${error.stderr ?? ""}`,
+  );
+}
+
+/**
+ * Run a shell command, like promisify(exec), logging it when debugging
+ */
+export async function execAsync(
+  command: string,
+  options: ExecOptions = {},
+): Promise<ExecOutput> {
+  logStart(command, options);
+  try {
+    const result = await execPromise(command, { ...options, encoding: "utf8" });
+    debugLog(`exit 0: ${command}`);
+    return result;
+  } catch (err) {
+    logFailure(command, err);
+    throw err;
+  }
+}
+
+/**
+ * Run a binary without a shell, like promisify(execFile), logging it when debugging
+ */
+export async function execFileAsync(
+  file: string,
+  args: string[],
+  options: ExecOptions = {},
+): Promise<ExecOutput> {
+  const command = [file, ...args].join(" ");
+  logStart(command, options);
+  const invocation = resolveInvocation(file, args);
+  try {
+    const result = await execFilePromise(invocation.file, invocation.args, {
+      ...options,
+      ...(invocation.shell ? { shell: true } : {}),
+      encoding: "utf8",
+    });
+    debugLog(`exit 0: ${command}`);
+    return result;
+  } catch (err) {
+    logFailure(command, err);
+    throw err;
+  }
+}
+
+export interface CommandResult extends ExecOutput {
+  /** Process exit code (127 when the command was not found) */
+  exitCode: number;
+}
+
+/**
+ * Error for a command that exited non-zero, carrying its exit code and output
+ */
+export class CommandError extends Error {
+  readonly exitCode: number;
+  readonly stdout: string;
+  readonly stderr: string;
+
+  constructor(command: string, result: CommandResult) {
+    const output = result.stderr.trim() || result.stdout.trim();
+    super(
+      `Command failed with exit code ${result.exitCode}: ${command}${output ? `\n${output}` : ""}`,
+    );
+    this.name = "CommandError";
+    this.exitCode = result.exitCode;
+    this.stdout = result.stdout;
+    this.stderr = result.stderr;
+  }
+}
+
+/**
+ * Get the exit code from a failed command error, if it has one.
+ * Spawn failures for a missing binary (ENOENT) map to 127 like a shell does.
+ */
+export function getExitCode(err: unknown): number | undefined {
+  const error = err as { exitCode?: unknown; code?: unknown };
+  if (typeof error?.exitCode === "number") return error.exitCode;
+  if (typeof error?.code === "number") return error.code;
+  if (error?.code === "ENOENT") return 127;
+  return undefined;
+}
+
+/**
+ * Run a binary without a shell and capture stdout, stderr, and exit code.
+ * Never rejects because of a non-zero exit; check result.exitCode instead.
+ */
+export async function runCommand(
+  file: string,
+  args: string[],
+  options: ExecOptions = {},
+): Promise<CommandResult> {
+  try {
+    const { stdout, stderr } = await execFileAsync(file, args, options);
+    return { stdout, stderr, exitCode: 0 };
+  } catch (err) {
+    const error = err as Error & { stdout?: string; stderr?: string };
+    return {
+      stdout: error.stdout ?? "",
+      stderr: error.stderr || error.message,
+      exitCode: getExitCode(err) ?? 1,
+    };
+  }
+}
+
+/**
+ * Run a binary like runCommand, throwing a CommandError on a non-zero exit
+ */
+export async function runCommandChecked(
+  file: string,
+  args: string[],
+  options: ExecOptions = {},
+): Promise<CommandResult> {
+  const result = await runCommand(file, args, options);
+  if (result.exitCode !== 0) {
+    throw new CommandError([file, ...args].join(" "), result);
+  }
+  return result;
+}
+
+// cmd.exe metacharacters; ^ makes cmd.exe pass the next one through as is
+const cmdMetaChars = /([()\][%!^"`<>&|;, *?])/g;
+
+function escapeCmdMetaChars(value: string): string {
+  return value.replace(cmdMetaChars, "^$1");
+}
+
+/**
+ * Quote an argument for a .cmd shim: first for the program's own argv
+ * parsing, then escape it twice, once for cmd.exe and once more for the
+ * batch file, which expands %VAR% and ^ again when it passes on %*
+ */
+function quoteForCmd(arg: string): string {
+  // Backslashes before a quote, or before the closing quote, are doubled
+  const escaped = arg.replace(/(\\*)"/g, '$1$1\\"').replace(/(\\*)$/, "$1$1");
+  return escapeCmdMetaChars(escapeCmdMetaChars(`"${escaped}"`));
+}
+
+/**
+ * How to start a binary without a shell where possible. On Windows, npm,
+ * npx, and CLIs installed through npm are .cmd shims that Node can only run
+ * through cmd.exe, so those get a shell with every argument quoted and every
+ * cmd.exe metacharacter (including % and ^) escaped.
+ */
+export function resolveInvocation(
+  file: string,
+  args: string[],
+): { file: string; args: string[]; shell: boolean } {
+  if (process.platform !== "win32") {
+    return { file, args, shell: false };
+  }
+  const resolved = findOnPath(file);
+  if (!resolved || !/\.(cmd|bat)$/i.test(resolved)) {
+    return { file, args, shell: false };
+  }
+  return {
+    file: escapeCmdMetaChars(resolved),
+    args: args.map(quoteForCmd),
+    shell: true,
+  };
+}
+
+/**
+ * Resolve a command the way a shell would: paths are checked directly, bare
+ * names are searched for on PATH. Returns null if it can't be found.
+ */
+export function findOnPath(command: string): string | null {
+  if (/[\\/]/.test(command)) {
+    return existsSync(command) ? command : null;
+  }
+
+  // On Windows a bare name like npx means npx.cmd, not the sh script next to it
+  const extensions =
+    process.platform === "win32" && !/\.\w+$/.test(command)
+      ? (process.env.PATHEXT ?? ".COM;.EXE;.BAT;.CMD").toLowerCase().split(";")
+      : [""];
+  for (const dir of (process.env.PATH ?? "").split(delimiter)) {
+    for (const ext of extensions) {
+      const candidate = join(dir, `${command}${ext}`);
+      if (dir && existsSync(candidate)) {
+        return candidate;
+      }
+    }
+  }
+  return null;
+}
@@ -1,25 +1,43 @@
-import { exec } from "node:child_process";
 import { join } from "node:path";
-import { promisify } from "node:util";
 import { packageRoot } from "../config.js";
-import { installMCPForClient } from "./mcpInstall.js";
+import { execAsync } from "./exec.js";
+import { findClientConfig, installMCPForClient } from "./mcpInstall.js";
 import { getPackageRunner } from "./packageManager.js";
 
-const execAsync = promisify(exec);
-
 export interface InstallOptions {
   devMode?: boolean;
   latest?: boolean;
+  // Aborting kills the in-flight install command
+  signal?: AbortSignal;
 }
 
 /**
  * Install Tiger MCP for the given IDE client
  */
-export async function installTigerMcp(clientName: string): Promise<void> {
+export async function installTigerMcp(
+  clientName: string,
+  signal?: AbortSignal,
+): Promise<void> {
+  if (findClientConfig(clientName)?.tigerInstallUnsupported) {
+    await installMCPForClient({
+      clientName,
+      serverName: "tiger",
+      command: "tiger",
+      args: ["mcp", "start"],
+      createBackup: false,
+    });
+    return;
+  }
+
   try {
-    await execAsync(`tiger mcp install ${clientName} --no-backup`);
+    await execAsync(`tiger mcp install ${clientName} --no-backup`, {
+      ...(signal ? { signal } : {}),
+    });
   } catch (err) {
     const error = err as Error & { stderr?: string };
+    if (signal?.aborted) {
+      throw error;
+    }
     // Ignore if already installed
     if (!error.stderr?.includes("already exists")) {
       throw new Error(`Failed to install Tiger MCP: ${error.message}`);
@@ -62,6 +80,7 @@ export async function install0peratorMcp(
     command,
     args,
     createBackup: false,
+    ...(options.signal ? { signal: options.signal } : {}),
   });
 }
 
@@ -72,6 +91,7 @@ export async function installBoth(
   clientName: string,
   options: InstallOptions = {},
 ): Promise<void> {
-  await installTigerMcp(clientName);
+  await installTigerMcp(clientName, options.signal);
+  options.signal?.throwIfAborted();
   await install0peratorMcp(clientName, options);
 }
@@ -8,7 +8,11 @@ import {
 import { homedir, tmpdir } from "node:os";
 import { join } from "node:path";
 import { afterEach, beforeEach, describe, expect, it, vi } from "vitest";
-import { addMCPServerViaJSON, expandPath } from "./mcpInstall.js";
+import {
+  addMCPServerViaJSON,
+  expandPath,
+  removeMCPServerViaJSON,
+} from "./mcpInstall.js";
 
 describe("addMCPServerViaJSON", () => {
   let testDir: string;
@@ -258,6 +262,90 @@ describe("addMCPServerViaJSON", () => {
     // comment-json handles trailing commas
     expect(content).toContain('"tiger"');
   });
+
+  it("should add server under context_servers in Zed settings", () => {
+    const configPath = join(testDir, "settings.json");
+    writeFileSync(
+      configPath,
+      `// Zed settings
+{
+  "theme": "One Dark",
+  "vim_mode": true,
+}`,
+    );
+
+    addMCPServerViaJSON(configPath, "/context_servers", "tiger", "tiger", [
+      "mcp",
+      "start",
+    ]);
+
+    const content = readFileSync(configPath, "utf-8");
+    expect(content).toContain("// Zed settings");
+    expect(JSON.parse(content.replace(/\/\/.*$/gm, ""))).toEqual({
+      theme: "One Dark",
+      vim_mode: true,
+      context_servers: {
+        tiger: {
+          command: "tiger",
+          args: ["mcp", "start"],
+        },
+      },
+    });
+  });
+});
+
+describe("removeMCPServerViaJSON", () => {
+  let testDir: string;
+
+  beforeEach(() => {
+    testDir = join(
+      tmpdir(),
+      `mcp-test-${Date.now()}-${Math.random().toString(36).slice(2)}`,
+    );
+    mkdirSync(testDir, { recursive: true });
+  });
+
+  afterEach(() => {
+    if (existsSync(testDir)) {
+      rmSync(testDir, { recursive: true, force: true });
+    }
+  });
+
+  it("should remove only the named server, preserving comments", () => {
+    const configPath = join(testDir, "mcp.json");
+    writeFileSync(
+      configPath,
+      `{
+  // Servers
+  "mcpServers": {
+    "0perator": { "command": "npx", "args": ["0perator@latest"] },
+    "other": { "command": "other", "args": [] }
+  }
+}`,
+    );
+
+    expect(removeMCPServerViaJSON(configPath, "/mcpServers", "0perator")).toBe(
+      true,
+    );
+
+    const content = readFileSync(configPath, "utf-8");
+    expect(content).toContain("// Servers");
+    expect(JSON.parse(content.replace(/\/\/.*$/gm, ""))).toEqual({
+      mcpServers: { other: { command: "other", args: [] } },
+    });
+  });
+
+  it("should return false when there is no entry", () => {
+    const configPath = join(testDir, "mcp.json");
+    writeFileSync(configPath, JSON.stringify({ mcpServers: {} }));
+
+    expect(removeMCPServerViaJSON(configPath, "/mcpServers", "0perator")).toBe(
+      false,
+    );
+    expect(
+      removeMCPServerViaJSON(join(testDir, "missing.json"), "/mcpServers", "x"),
+    ).toBe(false);
+  });
 });
 
 describe("expandPath", () => {
@@ -1,17 +1,15 @@
-import { exec } from "node:child_process";
 import {
   existsSync,
   mkdirSync,
   readFileSync,
   statSync,
+  unlinkSync,
   writeFileSync,
 } from "node:fs";
 import { homedir } from "node:os";
 import { dirname, join } from "node:path";
-import { promisify } from "node:util";
 import { parse, stringify } from "comment-json";
-
-const execAsync = promisify(exec);
+import { execAsync, runCommandChecked } from "./exec.js";
 
 // MCPServerConfig represents the MCP server configuration
 export interface MCPServerConfig {
@@ -33,6 +31,8 @@ export interface InstallOptions {
   createBackup?: boolean;
   // CustomConfigPath overrides the default config file location
   customConfigPath?: string;
+  // Signal aborts an in-flight client CLI install command
+  signal?: AbortSignal;
 }
 
 // ClientConfig represents our own client configuration for MCP installation
@@ -46,6 +46,12 @@ export interface ClientConfig {
     command: string,
     args: string[],
   ) => string[] | null;
+  // Command that removes a server, for clients whose config is managed by
+  // their own CLI. Other clients are edited at mcpServersPathPrefix.
+  buildRemoveCommand?: (serverName: string) => string[];
+  // Set when `tiger mcp install` doesn't know the client, so the Tiger MCP
+  // server is added to the config file like ours instead
+  tigerInstallUnsupported?: boolean;
 }
 
 // ClientInfo contains information about a supported MCP client
@@ -59,6 +65,7 @@ export const supportedClients: ClientConfig[] = [
   {
     name: "Claude Code",
     editorNames: ["claude-code"],
+    mcpServersPathPrefix: "/mcpServers",
     configPaths: ["~/.claude.json"],
     buildInstallCommand: (serverName, command, args) => [
       "claude",
@@ -70,6 +77,14 @@ export const supportedClients: ClientConfig[] = [
       command,
       ...args,
     ],
+    buildRemoveCommand: (serverName) => [
+      "claude",
+      "mcp",
+      "remove",
+      "-s",
+      "user",
+      serverName,
+    ],
   },
   {
     name: "Cursor",
@@ -95,10 +110,12 @@ export const supportedClients: ClientConfig[] = [
       command,
       ...args,
     ],
+    buildRemoveCommand: (serverName) => ["codex", "mcp", "remove", serverName],
   },
   {
     name: "Gemini CLI",
     editorNames: ["gemini", "gemini-cli"],
+    mcpServersPathPrefix: "/mcpServers",
     configPaths: ["~/.gemini/settings.json"],
     buildInstallCommand: (serverName, command, args) => [
       "gemini",
@@ -110,10 +127,19 @@ export const supportedClients: ClientConfig[] = [
       command,
       ...args,
     ],
+    buildRemoveCommand: (serverName) => [
+      "gemini",
+      "mcp",
+      "remove",
+      "-s",
+      "user",
+      serverName,
+    ],
   },
   {
     name: "VS Code",
     editorNames: ["vscode", "code", "vs-code"],
+    mcpServersPathPrefix: "/servers",
     configPaths: [
       "~/.config/Code/User/mcp.json",
       "~/Library/Application Support/Code/User/mcp.json",
@@ -137,6 +163,7 @@ export const supportedClients: ClientConfig[] = [
   {
     name: "Kiro CLI",
     editorNames: ["kiro-cli"],
+    mcpServersPathPrefix: "/mcpServers",
     configPaths: ["~/.kiro/settings/mcp.json"],
     buildInstallCommand: (serverName, command, args) => [
       "kiro-cli",
@@ -149,6 +176,25 @@ export const supportedClients: ClientConfig[] = [
       "--args",
       args.join(","),
     ],
+    buildRemoveCommand: (serverName) => [
+      "kiro-cli",
+      "mcp",
+      "remove",
+      "--name",
+      serverName,
+    ],
+  },
+  {
+    name: "Zed",
+    editorNames: ["zed"],
+    // Zed keeps MCP servers in its main settings file, next to the editor's
+    // own settings
+    mcpServersPathPrefix: "/context_servers",
+    configPaths: [
+      "~/.config/zed/settings.json",
+      "~/AppData/Roaming/Zed/settings.json",
+    ],
+    tigerInstallUnsupported: true,
   },
 ];
 
@@ -247,6 +293,32 @@ export function createConfigBackup(configPath: string): string | null {
   return backupPath;
 }
 
+/**
+ * Snapshot a client's config file and return a function that puts it back,
+ * removing the file if it didn't exist. Used to undo an interrupted install.
+ */
+export function snapshotClientConfig(clientName: string): () => void {
+  const clientCfg = findClientConfig(clientName);
+  const configPath = clientCfg
+    ? findClientConfigFile(clientCfg.configPaths)
+    : null;
+  if (!configPath) {
+    return () => {};
+  }
+
+  const existed = existsSync(configPath);
+  const content = existed ? readFileSync(configPath) : null;
+  const mode = existed ? statSync(configPath).mode : undefined;
+
+  return () => {
+    if (content) {
+      writeFileSync(configPath, content, { mode });
+    } else if (existsSync(configPath)) {
+      unlinkSync(configPath);
+    }
+  };
+}
+
 /**
  * Add MCP server using CLI command
  */
@@ -255,6 +327,7 @@ async function addMCPServerViaCLI(
   serverName: string,
   command: string,
   args: string[],
+  signal?: AbortSignal,
 ): Promise<void> {
   if (!clientCfg.buildInstallCommand) {
     throw new Error(
@@ -274,7 +347,9 @@ async function addMCPServerViaCLI(
   const [cmd, ...cmdArgs] = installCommand;
 
   try {
-    await execAsync(`${cmd} ${cmdArgs.map((a) => `"${a}"`).join(" ")}`);
+    await execAsync(`${cmd} ${cmdArgs.map((a) => `"${a}"`).join(" ")}`, {
+      ...(signal ? { signal } : {}),
+    });
   } catch (err) {
     const error = err as Error & { stderr?: string; stdout?: string };
     const output = error.stderr || error.stdout || "";
@@ -348,6 +423,138 @@ export function addMCPServerViaJSON(
   });
 }
 
+/**
+ * Read a parsed JSON config and the object holding its MCP servers, or null
+ * if the file or path doesn't exist
+ */
+function readMCPServers(
+  configPath: string,
+  mcpServersPathPrefix: string,
+): {
+  config: Record<string, unknown>;
+  servers: Record<string, unknown>;
+} | null {
+  if (!existsSync(configPath)) {
+    return null;
+  }
+  const content = readFileSync(configPath, "utf-8");
+  if (!content.trim()) {
+    return null;
+  }
+
+  let config: Record<string, unknown>;
+  try {
+    config = parse(content) as Record<string, unknown>;
+  } catch {
+    throw new Error(`Failed to parse existing config at ${configPath}`);
+  }
+
+  let current: unknown = config;
+  for (const part of mcpServersPathPrefix.split("/").filter((p) => p)) {
+    if (!current || typeof current !== "object" || !(part in current)) {
+      return null;
+    }
+    current = (current as Record<string, unknown>)[part];
+  }
+  if (!current || typeof current !== "object") {
+    return null;
+  }
+
+  return { config, servers: current as Record<string, unknown> };
+}
+
+/**
+ * Remove an MCP server from a JSON configuration file, preserving comments
+ * and other servers. Returns false if there was no entry to remove.
+ */
+export function removeMCPServerViaJSON(
+  configPath: string,
+  mcpServersPathPrefix: string,
+  serverName: string,
+): boolean {
+  const found = readMCPServers(configPath, mcpServersPathPrefix);
+  if (!found || !(serverName in found.servers)) {
+    return false;
+  }
+
+  delete found.servers[serverName];
+  writeFileSync(configPath, `${stringify(found.config, null, 2)}\n`, {
+    mode: statSync(configPath).mode,
+  });
+  return true;
+}
+
+/**
+ * Look up a server's entry in a client's config file. TOML configs (Codex)
+ * are only checked for the entry's table, so its fields come back empty.
+ */
+export function getMCPServerConfig(
+  clientCfg: ClientConfig,
+  configPath: string,
+  serverName: string,
+): Partial<MCPServerConfig> | null {
+  if (clientCfg.mcpServersPathPrefix) {
+    const found = readMCPServers(configPath, clientCfg.mcpServersPathPrefix);
+    const entry = found?.servers[serverName];
+    return entry && typeof entry === "object"
+      ? (entry as Partial<MCPServerConfig>)
+      : null;
+  }
+
+  // Codex keeps servers in TOML tables like [mcp_servers.name]
+  if (configPath.endsWith(".toml") && existsSync(configPath)) {
+    const headers = [
+      `[mcp_servers.${serverName}]`,
+      `[mcp_servers."${serverName}"]`,
+    ];
+    const found = readFileSync(configPath, "utf-8")
+      .split(/\r?\n/)
+      .some((line) => headers.includes(line.trim()));
+    return found ? {} : null;
+  }
+
+  return null;
+}
+
+/**
+ * Remove an MCP server from the specified client's configuration. Returns
+ * false if the client had no entry for it.
+ */
+export async function uninstallMCPForClient(
+  clientName: string,
+  serverName: string,
+): Promise<boolean> {
+  const clientCfg = findClientConfig(clientName);
+  if (!clientCfg) {
+    const supportedNames = getValidEditorNames();
+    throw new Error(
+      `Unsupported client: ${clientName}. Supported clients: ${supportedNames.join(", ")}`,
+    );
+  }
+
+  const configPath = findClientConfigFile(clientCfg.configPaths);
+  if (!configPath || !getMCPServerConfig(clientCfg, configPath, serverName)) {
+    return false;
+  }
+
+  if (clientCfg.buildRemoveCommand) {
+    const [cmd, ...cmdArgs] = clientCfg.buildRemoveCommand(serverName);
+    await runCommandChecked(cmd, cmdArgs);
+    return true;
+  }
+
+  if (!clientCfg.mcpServersPathPrefix) {
+    throw new Error(
+      `No MCP servers path prefix configured for ${clientCfg.name}`,
+    );
+  }
+  return removeMCPServerViaJSON(
+    configPath,
+    clientCfg.mcpServersPathPrefix,
+    serverName,
+  );
+}
+
 /**
  * Install MCP server configuration for the specified client
  * This is the main installation function that handles both CLI and JSON-based installation
@@ -402,6 +609,7 @@ export async function installMCPForClient(opts: InstallOptions): Promise<void> {
       opts.serverName,
       opts.command,
       opts.args,
+      opts.signal,
     );
   } else {
     // Use JSON patching approach for JSON-config clients
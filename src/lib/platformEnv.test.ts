@@ -0,0 +1,55 @@
+import {
+  chmodSync,
+  mkdirSync,
+  readFileSync,
+  rmSync,
+  writeFileSync,
+} from "node:fs";
+import { tmpdir } from "node:os";
+import { join } from "node:path";
+import { afterEach, beforeEach, describe, expect, it, vi } from "vitest";
+import { pushRailwayVariable } from "./platformEnv.js";
+
+// The fake CLI is a shell script
+describe.skipIf(process.platform === "win32")("pushRailwayVariable", () => {
+  let binDir: string;
+  let logFile: string;
+
+  beforeEach(() => {
+    binDir = join(
+      tmpdir(),
+      `railway-test-${Date.now()}-${Math.random().toString(36).slice(2)}`,
+    );
+    mkdirSync(binDir, { recursive: true });
+    logFile = join(binDir, "calls.log");
+    // Records its arguments, then whatever it reads from stdin
+    const railway = join(binDir, "railway");
+    writeFileSync(
+      railway,
+      `#!/bin/sh\necho "args: $*" >> "${logFile}"\necho "stdin: $(cat)" >> "${logFile}"\n`,
+    );
+    chmodSync(railway, 0o755);
+    vi.stubEnv("PATH", `${binDir}:${process.env.PATH ?? ""}`);
+  });
+
+  afterEach(() => {
+    vi.unstubAllEnvs();
+    rmSync(binDir, { recursive: true, force: true });
+  });
+
+  it("should pass the value on stdin, not on the command line", async () => {
+    await pushRailwayVariable(binDir, "SECRET", "s3cret", { deploy: true });
+
+    expect(readFileSync(logFile, "utf-8")).toBe(
+      "args: variable set SECRET --stdin\nstdin: s3cret\n",
+    );
+  });
+
+  it("should skip the redeploy when asked", async () => {
+    await pushRailwayVariable(binDir, "SECRET", "s3cret", { deploy: false });
+
+    expect(readFileSync(logFile, "utf-8")).toContain(
+      "args: variable set SECRET --stdin --skip-deploys\n",
+    );
+  });
+});
@@ -0,0 +1,169 @@
+import { spawn } from "node:child_process";
+import { existsSync } from "node:fs";
+import { join } from "node:path";
+import { debugLog, resolveInvocation } from "./exec.js";
+
+// Deployment platforms whose CLIs can receive environment variables
+export const deployPlatforms = ["vercel", "fly", "railway"] as const;
+export type DeployPlatform = (typeof deployPlatforms)[number];
+
+export const vercelEnvironments = [
+  "production",
+  "preview",
+  "development",
+] as const;
+export type VercelEnvironment = (typeof vercelEnvironments)[number];
+
+// Files that mark an app as linked to a platform, checked in order
+const platformMarkers: [DeployPlatform, string[]][] = [
+  ["vercel", [".vercel", "vercel.json"]],
+  ["fly", ["fly.toml"]],
+  ["railway", ["railway.json", "railway.toml", ".railway"]],
+];
+
+/**
+ * Detect which platform an app is deployed to from its config files
+ */
+export function detectPlatform(appDir: string): DeployPlatform | null {
+  for (const [platform, markers] of platformMarkers) {
+    if (markers.some((m) => existsSync(join(appDir, m)))) {
+      return platform;
+    }
+  }
+  return null;
+}
+
+/**
+ * Replace every secret value in a message so CLI errors can't leak them
+ */
+export function redactSecrets(message: string, values: string[]): string {
+  return values
+    .filter((v) => v.length > 0)
+    .reduce((msg, v) => msg.split(v).join("****"), message);
+}
+
+interface SpawnOptions {
+  cwd: string;
+  env?: Record<string, string>;
+  // Secrets are redacted from debug logs and error messages
+  secrets?: string[];
+}
+
+/**
+ * Run a command, writing input to its stdin so secrets stay off the command line
+ */
+function spawnWithInput(
+  file: string,
+  args: string[],
+  input: string,
+  { cwd, env = {}, secrets = [] }: SpawnOptions,
+): Promise<void> {
+  const command = redactSecrets(`${file} ${args.join(" ")}`, secrets);
+
+  return new Promise((resolve, reject) => {
+    debugLog(`$ ${command} (cwd: ${cwd})`);
+    const invocation = resolveInvocation(file, args);
+    const child = spawn(invocation.file, invocation.args, {
+      cwd,
+      shell: invocation.shell,
+      stdio: ["pipe", "pipe", "pipe"],
+      env: { ...process.env, ...env },
+    });
+
+    let stderr = "";
+    child.stderr.on("data", (data: Buffer) => {
+      stderr += data.toString();
+    });
+
+    child.on("error", (err) => {
+      reject(err);
+    });
+
+    child.on("exit", (code) => {
+      debugLog(`exit ${code ?? "unknown"}: ${command}`);
+      if (code === 0) {
+        resolve();
+      } else {
+        const errorMsg = stderr.trim() || `exit code ${code ?? "unknown"}`;
+        reject(new Error(redactSecrets(errorMsg, secrets)));
+      }
+    });
+
+    child.stdin.write(input);
+    child.stdin.end();
+  });
+}
+
+/**
+ * Add one variable to each of the given Vercel environments
+ */
+export async function pushVercelEnv(
+  appDir: string,
+  name: string,
+  value: string,
+  environments: readonly VercelEnvironment[],
+): Promise<void> {
+  for (const vercelEnv of environments) {
+    await spawnWithInput(
+      "npx",
+      [
+        "vercel",
+        "env",
+        "add",
+        name,
+        vercelEnv,
+        "--cwd",
+        appDir,
+        "--sensitive",
+        "--force",
+      ],
+      value,
+      {
+        cwd: appDir,
+        env: { VERCEL_TELEMETRY_DISABLED: "1" },
+        secrets: [value],
+      },
+    );
+  }
+}
+
+/**
+ * Set Fly.io secrets in one release via `fly secrets import` on stdin
+ */
+export async function pushFlySecrets(
+  appDir: string,
+  vars: Record<string, string>,
+): Promise<void> {
+  const input = Object.entries(vars)
+    .map(([name, value]) =>
+      value.includes("\n") ? `${name}="""${value}"""` : `${name}=${value}`,
+    )
+    .join("\n");
+
+  await spawnWithInput("fly", ["secrets", "import"], `${input}\n`, {
+    cwd: appDir,
+    secrets: Object.values(vars),
+  });
+}
+
+/**
+ * Set one Railway service variable with `railway variable set --stdin`, so
+ * the value never appears on the command line. Pass deploy: false to set
+ * several variables before a single redeploy.
+ */
+export async function pushRailwayVariable(
+  appDir: string,
+  name: string,
+  value: string,
+  { deploy }: { deploy: boolean },
+): Promise<void> {
+  const args = ["variable", "set", name, "--stdin"];
+  if (!deploy) {
+    args.push("--skip-deploys");
+  }
+
+  await spawnWithInput("railway", args, value, {
+    cwd: appDir,
+    secrets: [value],
+  });
+}
@@ -1,7 +1,15 @@
-import { mkdir, readdir, readFile, writeFile } from "node:fs/promises";
-import { dirname, join, relative } from "node:path";
+import { existsSync, statSync } from "node:fs";
+import {
+  copyFile,
+  mkdir,
+  readdir,
+  readFile,
+  writeFile,
+} from "node:fs/promises";
+import { dirname, join, relative, resolve, sep } from "node:path";
 import Handlebars from "handlebars";
-import { templatesDir } from "../config.js";
+import { templatesDir, userTemplatesDir } from "../config.js";
+import { execFileAsync } from "./exec.js";
 
 export interface AppTemplateVars {
   app_name: string;
@@ -10,19 +18,94 @@ export interface AppTemplateVars {
   future_features?: string | undefined;
   db_schema?: string | undefined;
   db_user?: string | undefined;
+  // Filled from the app's package.json or git config when not given
+  description?: string | undefined;
+  author?: string | undefined;
+  license?: string | undefined;
+  year?: number | undefined;
+  framework?: string | undefined;
+}
+
+interface PackageInfo {
+  description?: string | undefined;
+  author?: string | undefined;
+  license?: string | undefined;
+}
+
+async function readPackageInfo(appDir: string): Promise<PackageInfo> {
+  try {
+    const pkg = JSON.parse(
+      await readFile(join(appDir, "package.json"), "utf-8"),
+    ) as {
+      description?: string;
+      author?: string | { name?: string };
+      license?: string;
+    };
+    return {
+      description: pkg.description || undefined,
+      author:
+        (typeof pkg.author === "string" ? pkg.author : pkg.author?.name) ||
+        undefined,
+      license: pkg.license || undefined,
+    };
+  } catch {
+    return {};
+  }
+}
+
+async function getGitAuthor(cwd: string): Promise<string | undefined> {
+  try {
+    const { stdout } = await execFileAsync("git", ["config", "user.name"], {
+      cwd,
+    });
+    return stdout.trim() || undefined;
+  } catch {
+    return undefined;
+  }
+}
+
+/**
+ * Fill in project metadata the caller didn't pass: description, author, and
+ * license from the app's package.json (author falls back to git config),
+ * plus the current year and framework
+ */
+export async function resolveTemplateVars(
+  appDir: string,
+  vars: AppTemplateVars,
+): Promise<AppTemplateVars> {
+  const pkg = await readPackageInfo(appDir);
+  return {
+    ...vars,
+    description: vars.description ?? pkg.description,
+    author: vars.author ?? pkg.author ?? (await getGitAuthor(appDir)),
+    license: vars.license ?? pkg.license,
+    year: vars.year ?? new Date().getFullYear(),
+    framework: vars.framework ?? "Next.js",
+  };
 }
 
 type ContentTransform = (content: string) => string;
 
 /**
- * Copy a template directory to destination, optionally transforming file contents
+ * Path of a user override for a built-in template file, or null if none exists
+ */
+function findTemplateOverride(relPath: string): string | null {
+  const overridePath = join(userTemplatesDir, relPath);
+  return existsSync(overridePath) ? overridePath : null;
+}
+
+/**
+ * Copy a template directory to destination, optionally transforming file contents.
+ * Files in the user templates directory take precedence over built-in ones,
+ * unless a custom template directory replaces the built-in set entirely.
  */
 async function copyTemplateDir(
   templateName: string,
   destDir: string,
   transform?: ContentTransform,
+  customDir?: string,
 ): Promise<void> {
-  const srcBaseDir = join(templatesDir, templateName);
+  const srcBaseDir = customDir ?? join(templatesDir, templateName);
 
   async function copyDir(srcDir: string): Promise<void> {
     const entries = await readdir(srcDir, { withFileTypes: true });
@@ -38,7 +121,10 @@ async function copyTemplateDir(
       } else {
         await mkdir(dirname(destPath), { recursive: true });
 
-        const content = await readFile(srcPath, "utf-8");
+        const overridePath = customDir
+          ? null
+          : findTemplateOverride(join(templateName, relPath));
+        const content = await readFile(overridePath ?? srcPath, "utf-8");
         const output = transform ? transform(content) : content;
         await writeFile(destPath, output);
       }
@@ -48,17 +134,44 @@ async function copyTemplateDir(
   await copyDir(srcBaseDir);
 }
 
+// Files in the app template that the create-app skill relies on
+export const requiredAppTemplateFiles = [
+  "tsconfig.server.json",
+  "src/styles/globals.css.orange",
+] as const;
+
 /**
- * Write app templates with Handlebars templating
+ * Check that a custom app template directory exists and has the required
+ * files. Returns the missing files; throws if it isn't a directory.
+ */
+export function validateAppTemplateDir(templateDir: string): string[] {
+  if (!existsSync(templateDir) || !statSync(templateDir).isDirectory()) {
+    throw new Error(`Template directory not found: ${templateDir}`);
+  }
+  return requiredAppTemplateFiles.filter(
+    (file) => !existsSync(join(templateDir, file)),
+  );
+}
+
+/**
+ * Write app templates with Handlebars templating, from the built-in app
+ * template or a custom template directory
  */
 export async function writeAppTemplates(
   destDir: string,
   vars: AppTemplateVars,
+  templateDir?: string,
 ): Promise<void> {
-  await copyTemplateDir("app", destDir, (content) => {
-    const template = Handlebars.compile(content);
-    return template(vars);
-  });
+  const data = await resolveTemplateVars(destDir, vars);
+  await copyTemplateDir(
+    "app",
+    destDir,
+    (content) => {
+      const template = Handlebars.compile(content);
+      return template(data);
+    },
+    templateDir,
+  );
 }
 
 /**
@@ -75,8 +188,66 @@ export async function writeClaudeMdTemplate(
   destDir: string,
   vars: AppTemplateVars,
 ): Promise<void> {
+  const data = await resolveTemplateVars(destDir, vars);
   await copyTemplateDir("claude-md", destDir, (content) => {
     const template = Handlebars.compile(content);
-    return template(vars);
+    return template(data);
+  });
+}
+
+export interface TemplateFile {
+  // Path relative to the templates directory, e.g. app/biome.jsonc
+  path: string;
+  overridden: boolean;
+}
+
+/**
+ * List every built-in template file and whether the user has overridden it
+ */
+export async function listTemplateFiles(): Promise<TemplateFile[]> {
+  const entries = await readdir(templatesDir, {
+    recursive: true,
+    withFileTypes: true,
   });
+
+  return entries
+    .filter((entry) => entry.isFile())
+    .map((entry) => {
+      const path = relative(templatesDir, join(entry.parentPath, entry.name))
+        .split(sep)
+        .join("/");
+      return { path, overridden: findTemplateOverride(path) !== null };
+    })
+    .sort((a, b) => a.path.localeCompare(b.path));
+}
+
+/**
+ * Copy a built-in template file into the user templates directory for editing.
+ * Returns the path of the copy.
+ */
+export async function ejectTemplate(
+  templatePath: string,
+  force = false,
+): Promise<string> {
+  const srcPath = resolve(templatesDir, templatePath);
+  const isTemplateFile =
+    srcPath.startsWith(templatesDir + sep) &&
+    existsSync(srcPath) &&
+    statSync(srcPath).isFile();
+  if (!isTemplateFile) {
+    throw new Error(
+      `Unknown template: ${templatePath}. Run '0perator templates list' to see available templates.`,
+    );
+  }
+
+  const destPath = join(userTemplatesDir, relative(templatesDir, srcPath));
+  if (existsSync(destPath) && !force) {
+    throw new Error(
+      `${destPath} already exists. Use --force to overwrite it with the built-in template.`,
+    );
+  }
+
+  await mkdir(dirname(destPath), { recursive: true });
+  await copyFile(srcPath, destPath);
+  return destPath;
 }
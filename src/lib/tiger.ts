@@ -0,0 +1,233 @@
+import { execFileAsync, getExitCode } from "./exec.js";
+
+// TigerOutput is the captured output of a tiger CLI invocation
+export interface TigerOutput {
+  stdout: string;
+  stderr: string;
+}
+
+// TigerRunner runs the tiger CLI. Tools receive it through the server context
+// so tests can substitute a fake instead of calling the real binary.
+export interface TigerRunner {
+  run(args: string[]): Promise<TigerOutput>;
+}
+
+// Compute configurations accepted by `tiger service create`
+// (CPU in millicores, memory in GB). "shared" is the free tier.
+export const computeConfigs = [
+  { cpu: "shared", memory: "shared" },
+  { cpu: "500", memory: "2" },
+  { cpu: "1000", memory: "4" },
+  { cpu: "2000", memory: "8" },
+  { cpu: "4000", memory: "16" },
+  { cpu: "8000", memory: "32" },
+  { cpu: "16000", memory: "64" },
+  { cpu: "32000", memory: "128" },
+] as const;
+
+export const serviceAddons = ["time-series", "ai"] as const;
+
+// CreateServiceOptions configures a new Tiger Cloud service
+export interface CreateServiceOptions {
+  name: string;
+  cpu: string;
+  memory: string;
+  addons: string[];
+  // Region pins the service to a cloud region (default: chosen by Tiger Cloud)
+  region?: string | undefined;
+}
+
+// TigerService is the subset of `tiger service get` output the tools use
+export interface TigerService {
+  service_id?: string;
+  connection_string?: string;
+}
+
+/**
+ * Default runner that executes the tiger binary on PATH
+ */
+export const execTigerRunner: TigerRunner = {
+  run: async (args) => {
+    const { stdout, stderr } = await execFileAsync("tiger", args);
+    return { stdout, stderr };
+  },
+};
+
+/**
+ * Parse JSON printed by the tiger CLI, tolerating banners or warnings around it
+ */
+export function parseTigerJson<T>(stdout: string): T {
+  try {
+    return JSON.parse(stdout) as T;
+  } catch {
+    const start = stdout.indexOf("{");
+    const end = stdout.lastIndexOf("}");
+    if (start === -1 || end <= start) {
+      throw new Error(`No JSON object in tiger output: ${stdout}`);
+    }
+    try {
+      return JSON.parse(stdout.slice(start, end + 1)) as T;
+    } catch {
+      throw new Error(`Failed to parse tiger output as JSON: ${stdout}`);
+    }
+  }
+}
+
+// Output of a tiger command that failed because the CLI isn't logged in
+const notAuthenticated =
+  /not (logged in|authenticated)|auth login|unauthorized/i;
+
+/**
+ * Convert a failed tiger invocation into an Error with a helpful message.
+ * The returned error carries the command's exitCode when known.
+ */
+export function tigerError(
+  action: string,
+  err: unknown,
+): Error & { exitCode?: number } {
+  const error = err as Error & { stdout?: string; stderr?: string };
+  const output = `${error.stdout || ""}${error.stderr || ""}`;
+  const exitCode = getExitCode(err);
+  const withExitCode = (message: string) =>
+    Object.assign(
+      new Error(message),
+      exitCode === undefined ? {} : { exitCode },
+    );
+
+  if (exitCode === 127) {
+    return withExitCode(
+      `Failed to ${action}: the tiger CLI is not installed or not on PATH. Run '0perator init' to install it.`,
+    );
+  }
+
+  if (notAuthenticated.test(output)) {
+    return withExitCode(
+      `Failed to ${action}: not authenticated with Tiger Cloud. Run 'tiger auth login' and try again.`,
+    );
+  }
+
+  return withExitCode(`Failed to ${action}: ${error.message}\n${output}`);
+}
+
+export type TigerAuthStatus =
+  | { state: "authenticated" | "not_authenticated" | "not_installed" }
+  // The check itself failed (e.g. network error), so the login is unknown
+  | { state: "error"; message: string };
+
+/**
+ * Report whether the tiger CLI is installed and logged in to Tiger Cloud
+ */
+export async function getTigerAuthStatus(
+  tiger: TigerRunner = execTigerRunner,
+): Promise<TigerAuthStatus> {
+  try {
+    await tiger.run(["auth", "whoami"]);
+    return { state: "authenticated" };
+  } catch (err) {
+    if (getExitCode(err) === 127) {
+      return { state: "not_installed" };
+    }
+
+    const error = err as Error & { stdout?: string; stderr?: string };
+    const output = `${error.stdout || ""}${error.stderr || ""}`;
+    if (notAuthenticated.test(output)) {
+      return { state: "not_authenticated" };
+    }
+    return { state: "error", message: error.stderr?.trim() || error.message };
+  }
+}
+
+/**
+ * Check that a CPU/memory pair is one of the supported compute configurations
+ */
+export function isValidCompute(cpu: string, memory: string): boolean {
+  return computeConfigs.some((c) => c.cpu === cpu && c.memory === memory);
+}
+
+/**
+ * Describe a compute configuration for display (e.g. "1 CPU / 4 GB")
+ */
+export function describeCompute(cpu: string, memory: string): string {
+  if (cpu === "shared") {
+    return "shared (free tier)";
+  }
+  return `${Number(cpu) / 1000} CPU / ${memory} GB`;
+}
+
+/**
+ * Build the `tiger service create` arguments for the given options
+ */
+export function buildCreateServiceArgs(
+  options: CreateServiceOptions,
+): string[] {
+  const regionArgs = options.region ? ["--region", options.region] : [];
+  return [
+    "service",
+    "create",
+    "--name",
+    options.name,
+    "--cpu",
+    options.cpu,
+    "--memory",
+    options.memory,
+    "--addons",
+    options.addons.join(","),
+    ...regionArgs,
+    "--no-wait",
+    "-o",
+    "json",
+  ];
+}
+
+/**
+ * Create a Tiger Cloud service without waiting for it to become ready
+ */
+export async function createService(
+  runner: TigerRunner,
+  options: CreateServiceOptions,
+): Promise<string> {
+  let output: TigerOutput;
+  try {
+    output = await runner.run(buildCreateServiceArgs(options));
+  } catch (err) {
+    throw tigerError("create database", err);
+  }
+
+  const result = parseTigerJson<TigerService>(output.stdout);
+  if (!result.service_id) {
+    throw new Error(
+      `No service_id in response: ${output.stdout}${output.stderr}`,
+    );
+  }
+
+  return result.service_id;
+}
+
+/**
+ * Get the admin connection string (with password) for a Tiger Cloud service
+ */
+export async function getConnectionString(
+  runner: TigerRunner,
+  serviceId: string,
+): Promise<string> {
+  let output: TigerOutput;
+  try {
+    output = await runner.run([
+      "service",
+      "get",
+      serviceId,
+      "--with-password",
+      "-o",
+      "json",
+    ]);
+  } catch (err) {
+    throw tigerError("get service details", err);
+  }
+
+  const service = parseTigerJson<TigerService>(output.stdout);
+  if (!service.connection_string) {
+    throw new Error("connection_string not found in service details");
+  }
+
+  return service.connection_string;
+}
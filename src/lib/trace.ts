@@ -0,0 +1,76 @@
+import { appendFile, mkdir } from "node:fs/promises";
+import { join } from "node:path";
+import { log } from "@tigerdata/mcp-boilerplate";
+import { tracesDir } from "../config.js";
+
+// Argument and result keys whose values are never written to a trace
+const secretKey = /password|secret|token|api_?key|database_url|credential/i;
+
+/**
+ * Whether OPERATOR_TRACE is set, which records every tool call to a file
+ */
+export function isTraceEnabled(): boolean {
+  const value = process.env.OPERATOR_TRACE?.toLowerCase();
+  return value === "1" || value === "true";
+}
+
+/**
+ * Copy a value with secrets masked: values under secret-looking keys, and
+ * passwords embedded in connection strings
+ */
+export function redactTraceValue(value: unknown, key = ""): unknown {
+  if (typeof value === "string") {
+    if (key && secretKey.test(key)) {
+      return "[redacted]";
+    }
+    return value.replace(/(\/\/[^:/@\s]+:)[^@\s]+@/g, "$1***@");
+  }
+  if (Array.isArray(value)) {
+    return value.map((item) => redactTraceValue(item));
+  }
+  if (value && typeof value === "object") {
+    return Object.fromEntries(
+      Object.entries(value).map(([k, v]) => [k, redactTraceValue(v, k)]),
+    );
+  }
+  return value;
+}
+
+export interface TraceEntry {
+  tool: string;
+  args: unknown;
+  duration_ms: number;
+  result?: unknown;
+  error?: string;
+}
+
+// One JSON Lines file per server process
+const traceFile = join(
+  tracesDir,
+  `${new Date().toISOString().replace(/[:.]/g, "-")}-${process.pid}.jsonl`,
+);
+
+/**
+ * Append a tool call to this process's trace file. Tracing never fails the
+ * tool call; write errors are logged and dropped.
+ */
+export async function writeTrace(entry: TraceEntry): Promise<void> {
+  const line = JSON.stringify({
+    time: new Date().toISOString(),
+    tool: entry.tool,
+    args: redactTraceValue(entry.args),
+    duration_ms: entry.duration_ms,
+    ...(entry.error !== undefined
+      ? { error: redactTraceValue(entry.error) }
+      : { result: redactTraceValue(entry.result) }),
+  });
+
+  try {
+    await mkdir(tracesDir, { recursive: true });
+    await appendFile(traceFile, `${line}\n`, { mode: 0o600 });
+  } catch (err) {
+    log.warn(
+      `Failed to write trace to ${traceFile}: ${(err as Error).message}`,
+    );
+  }
+}
@@ -0,0 +1,58 @@
+import { describe, expect, it } from "vitest";
+import {
+  readTruncatedField,
+  truncateLargeFields,
+  truncateString,
+} from "./truncate.js";
+
+describe("truncateString", () => {
+  it("should keep strings within the limit", () => {
+    expect(truncateString("abc", 3)).toBe("abc");
+  });
+
+  it("should keep the start and report the dropped bytes", () => {
+    expect(truncateString("abcdef", 4)).toMatch(
+      /^abcd\n\[truncated 2 bytes; /,
+    );
+  });
+
+  it("should not split a multi-byte character", () => {
+    expect(truncateString("aé", 2)).toMatch(/^a\n\[truncated 2 bytes; /);
+  });
+});
+
+describe("truncateLargeFields", () => {
+  it("should truncate nested string fields only", () => {
+    const result = truncateLargeFields(
+      { success: false, message: "x".repeat(10), files: ["y".repeat(10)] },
+      5,
+    );
+    expect(result.success).toBe(false);
+    expect(result.message).toMatch(/^x{5}\n\[truncated 5 bytes; /);
+    expect(result.files[0]).toMatch(/^y{5}\n\[truncated 5 bytes; /);
+  });
+});
+
+describe("readTruncatedField", () => {
+  it("should page through the rest of a truncated field", () => {
+    const truncated = truncateString("aébcdef", 2, "read_truncated");
+    const match = truncated.match(/with id "(\w+)" and offset (\d+)/);
+    const id = match?.[1] ?? "";
+    const offset = Number(match?.[2]);
+
+    expect(truncated).toMatch(/^a\n\[truncated 7 bytes; /);
+    expect(readTruncatedField(id, offset, 3)).toEqual({
+      text: "éb",
+      total_bytes: 8,
+      next_offset: 4,
+    });
+    expect(readTruncatedField(id, 7, 3)).toEqual({
+      text: "f",
+      total_bytes: 8,
+    });
+  });
+
+  it("should return null for an unknown id", () => {
+    expect(readTruncatedField("missing", 0)).toBeNull();
+  });
+});
@@ -0,0 +1,111 @@
+// Longest string field a tool result may carry. Larger payloads (build logs,
+// command output) are rejected by some clients and waste the model's context.
+export const maxFieldBytes = 16 * 1024;
+
+// Full text of recently truncated fields, so the rest can be read in pages.
+// Only the newest few are kept.
+const maxStoredFields = 20;
+const storedFields = new Map<string, string>();
+let nextFieldId = 1;
+
+/**
+ * Decode at most maxBytes of UTF-8, dropping a multi-byte character split
+ * at the cut
+ */
+function decodeHead(bytes: Buffer, maxBytes: number): string {
+  return bytes
+    .subarray(0, maxBytes)
+    .toString("utf8")
+    .replace(/\uFFFD$/, "");
+}
+
+/**
+ * Cut a string to at most maxBytes of UTF-8, keeping the start and noting
+ * how many bytes were dropped. With readTool, the full string is kept and
+ * the note says how to read the rest with that tool.
+ */
+export function truncateString(
+  value: string,
+  maxBytes = maxFieldBytes,
+  readTool?: string,
+): string {
+  const bytes = Buffer.from(value, "utf8");
+  if (bytes.length <= maxBytes) {
+    return value;
+  }
+
+  const head = decodeHead(bytes, maxBytes);
+  const offset = Buffer.byteLength(head, "utf8");
+  const dropped = bytes.length - offset;
+  if (!readTool) {
+    return `${head}\n[truncated ${dropped} bytes; set OPERATOR_TRACE=1 to record the full result]`;
+  }
+
+  const id = `t${nextFieldId++}`;
+  storedFields.set(id, value);
+  if (storedFields.size > maxStoredFields) {
+    const [oldest] = storedFields.keys();
+    if (oldest) {
+      storedFields.delete(oldest);
+    }
+  }
+  return `${head}\n[truncated ${dropped} bytes; call ${readTool} with id "${id}" and offset ${offset} to read the rest]`;
+}
+
+export interface TruncatedPage {
+  text: string;
+  total_bytes: number;
+  // Offset of the next page, if there is one
+  next_offset?: number;
+}
+
+/**
+ * Read up to maxBytes of a truncated field from a byte offset, or null if
+ * the field is no longer stored
+ */
+export function readTruncatedField(
+  id: string,
+  offset: number,
+  maxBytes = maxFieldBytes,
+): TruncatedPage | null {
+  const value = storedFields.get(id);
+  if (value === undefined) {
+    return null;
+  }
+
+  const bytes = Buffer.from(value, "utf8");
+  const text = decodeHead(bytes.subarray(offset), maxBytes);
+  const end = offset + Buffer.byteLength(text, "utf8");
+  return {
+    text,
+    total_bytes: bytes.length,
+    ...(end < bytes.length ? { next_offset: end } : {}),
+  };
+}
+
+/**
+ * Copy a tool result with every oversized string field truncated
+ */
+export function truncateLargeFields<T>(
+  value: T,
+  maxBytes = maxFieldBytes,
+  readTool?: string,
+): T {
+  if (typeof value === "string") {
+    return truncateString(value, maxBytes, readTool) as T;
+  }
+  if (Array.isArray(value)) {
+    return value.map((item) =>
+      truncateLargeFields(item, maxBytes, readTool),
+    ) as T;
+  }
+  if (value && typeof value === "object") {
+    return Object.fromEntries(
+      Object.entries(value).map(([k, v]) => [
+        k,
+        truncateLargeFields(v, maxBytes, readTool),
+      ]),
+    ) as T;
+  }
+  return value;
+}
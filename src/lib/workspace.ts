@@ -0,0 +1,146 @@
+import { existsSync } from "node:fs";
+import { readFile, writeFile } from "node:fs/promises";
+import { dirname, join, parse } from "node:path";
+import { getPackageManager } from "./packageManager.js";
+
+export interface Workspace {
+  // Root is the directory holding the workspace config
+  root: string;
+  // PackageManager is the manager that owns the workspace lockfile
+  packageManager: Awaited<ReturnType<typeof getPackageManager>>;
+  // Config is the file listing workspace packages
+  config: "pnpm-workspace.yaml" | "package.json";
+  // Patterns are the workspace package globs
+  patterns: string[];
+}
+
+/**
+ * Read workspace globs from pnpm-workspace.yaml (packages: list)
+ */
+function parsePnpmWorkspace(content: string): string[] {
+  const patterns: string[] = [];
+  let inPackages = false;
+  for (const line of content.split("\n")) {
+    if (/^packages\s*:/.test(line)) {
+      inPackages = true;
+      continue;
+    }
+    if (inPackages) {
+      const match = line.match(/^\s+-\s*["']?([^"'#]+?)["']?\s*(#.*)?$/);
+      if (match?.[1]) {
+        patterns.push(match[1]);
+      } else if (/^\S/.test(line)) {
+        break;
+      }
+    }
+  }
+  return patterns;
+}
+
+/**
+ * Read workspace globs from package.json (workspaces array or object)
+ */
+function parsePackageJsonWorkspaces(content: string): string[] | null {
+  const pkg = JSON.parse(content) as {
+    workspaces?: string[] | { packages?: string[] };
+  };
+  if (Array.isArray(pkg.workspaces)) return pkg.workspaces;
+  if (pkg.workspaces?.packages) return pkg.workspaces.packages;
+  return null;
+}
+
+/**
+ * Find the enclosing pnpm/npm/yarn/bun workspace by walking up from startDir
+ */
+export async function findWorkspace(
+  startDir: string,
+): Promise<Workspace | null> {
+  let dir = startDir;
+  const { root: fsRoot } = parse(startDir);
+
+  while (true) {
+    const pnpmConfig = join(dir, "pnpm-workspace.yaml");
+    if (existsSync(pnpmConfig)) {
+      return {
+        root: dir,
+        packageManager: "pnpm",
+        config: "pnpm-workspace.yaml",
+        patterns: parsePnpmWorkspace(await readFile(pnpmConfig, "utf-8")),
+      };
+    }
+
+    const pkgPath = join(dir, "package.json");
+    if (existsSync(pkgPath)) {
+      try {
+        const patterns = parsePackageJsonWorkspaces(
+          await readFile(pkgPath, "utf-8"),
+        );
+        if (patterns) {
+          return {
+            root: dir,
+            packageManager: await getPackageManager(dir),
+            config: "package.json",
+            patterns,
+          };
+        }
+      } catch {
+        // Unparseable package.json; keep looking further up
+      }
+    }
+
+    if (dir === fsRoot) return null;
+    dir = dirname(dir);
+  }
+}
+
+/**
+ * Pick the directory new apps go in: apps/ if present or listed, else packages/
+ */
+export function getWorkspaceAppsDir(workspace: Workspace): string {
+  const listsApps = workspace.patterns.some((p) => p.startsWith("apps/"));
+  if (listsApps || existsSync(join(workspace.root, "apps"))) return "apps";
+  return "packages";
+}
+
+/**
+ * Add `${appsDir}/*` to the workspace config unless a pattern already covers
+ * it. Returns a function that restores the previous config, or null if
+ * nothing changed.
+ */
+export async function registerWorkspaceDir(
+  workspace: Workspace,
+  appsDir: string,
+): Promise<(() => Promise<void>) | null> {
+  const pattern = `${appsDir}/*`;
+  if (
+    workspace.patterns.some((p) => p === pattern || p === `${appsDir}/**`)
+  ) {
+    return null;
+  }
+
+  const configPath = join(workspace.root, workspace.config);
+  const content = await readFile(configPath, "utf-8");
+
+  if (workspace.config === "pnpm-workspace.yaml") {
+    const updated = /^packages\s*:/m.test(content)
+      ? content.replace(/^packages\s*:.*$/m, `$&\n  - "${pattern}"`)
+      : `${content.trimEnd()}\npackages:\n  - "${pattern}"\n`;
+    await writeFile(configPath, updated);
+  } else {
+    const pkg = JSON.parse(content) as {
+      workspaces: string[] | { packages: string[] };
+    };
+    if (Array.isArray(pkg.workspaces)) {
+      pkg.workspaces.push(pattern);
+    } else {
+      pkg.workspaces.packages.push(pattern);
+    }
+    await writeFile(configPath, `${JSON.stringify(pkg, null, 2)}\n`);
+  }
+
+  workspace.patterns.push(pattern);
+  return async () => {
+    await writeFile(configPath, content);
+    workspace.patterns.splice(workspace.patterns.indexOf(pattern), 1);
+  };
+}
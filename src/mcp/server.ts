@@ -1,12 +1,26 @@
 import { stdioServerFactory } from "@tigerdata/mcp-boilerplate";
 import { context, serverInfo } from "./serverInfo.js";
-import { getApiFactories } from "./tools/index.js";
+import { getApiFactories, type ToolOptions } from "./tools/index.js";
+
+/**
+ * Send console output to stderr. Stdout carries the MCP protocol, so a stray
+ * console.log (e.g. postgres.js printing a NOTICE) would corrupt it.
+ */
+function redirectConsoleToStderr(): void {
+  console.log = console.error;
+  console.info = console.error;
+  console.debug = console.error;
+}
 
 /**
  * Start the MCP server in stdio mode
  */
-export async function startMcpServer(): Promise<void> {
-  const apiFactories = await getApiFactories();
+export async function startMcpServer(
+  toolOptions: ToolOptions = {},
+): Promise<void> {
+  redirectConsoleToStderr();
+
+  const apiFactories = await getApiFactories(toolOptions);
 
   await stdioServerFactory({
     ...serverInfo,
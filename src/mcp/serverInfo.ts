@@ -1,4 +1,5 @@
 import { version } from "../config.js";
+import { execTigerRunner } from "../lib/tiger.js";
 import type { ServerContext } from "../types.js";
 
 export const serverInfo = {
@@ -6,4 +7,6 @@ export const serverInfo = {
   version,
 } as const;
 
-export const context: ServerContext = {};
+export const context: ServerContext = {
+  tiger: execTigerRunner,
+};
@@ -0,0 +1,350 @@
+import { existsSync } from "node:fs";
+import { writeFile } from "node:fs/promises";
+import { join, resolve } from "node:path";
+import type { ApiFactory } from "@tigerdata/mcp-boilerplate";
+import postgres from "postgres";
+import { z } from "zod";
+import { databaseUrlInputs, resolveDatabaseUrl } from "../../lib/database.js";
+import type { ServerContext } from "../../types.js";
+
+const identifier = /^[a-z_][a-z0-9_]*$/;
+const identifierMessage = "Must be a lowercase identifier";
+
+const buckets = {
+  hourly: { width: "1 hour", startOffset: "3 days", schedule: "30 minutes" },
+  daily: { width: "1 day", startOffset: "30 days", schedule: "1 hour" },
+} as const;
+
+const aggregateFunctions = ["count", "sum", "avg", "min", "max"] as const;
+
+const inputSchema = {
+  ...databaseUrlInputs,
+  hypertable: z
+    .string()
+    .regex(
+      /^([a-z_][a-z0-9_]*\.)?[a-z_][a-z0-9_]*$/,
+      "Table must be a lowercase identifier, optionally schema-qualified",
+    )
+    .describe("Source hypertable, e.g. events or my_app.events"),
+  time_column: z
+    .string()
+    .regex(identifier, identifierMessage)
+    .describe("Time column the hypertable is partitioned by"),
+  bucket: z
+    .enum(["hourly", "daily"])
+    .default("hourly")
+    .describe("Bucket width for the aggregate"),
+  group_by: z
+    .array(z.string().regex(identifier, identifierMessage))
+    .default([])
+    .describe("Columns to group by in addition to the time bucket"),
+  aggregates: z
+    .array(
+      z.object({
+        fn: z.enum(aggregateFunctions),
+        column: z
+          .string()
+          .regex(identifier, identifierMessage)
+          .optional()
+          .describe("Column to aggregate (omit for count)"),
+      }),
+    )
+    .default([{ fn: "count" }])
+    .describe("Aggregates to compute per bucket, e.g. count, avg of value"),
+  view_name: z
+    .string()
+    .regex(identifier, identifierMessage)
+    .optional()
+    .describe("Name of the view (default: <table>_<bucket>)"),
+  write_helper: z
+    .boolean()
+    .default(true)
+    .describe(
+      "Write a typed query helper to src/server/db/<view_name>.ts in application_directory",
+    ),
+} as const;
+
+const outputSchema = {
+  success: z.boolean().describe("Whether the continuous aggregate was created"),
+  message: z.string().describe("Status message"),
+  status: z
+    .enum(["created", "skipped"])
+    .optional()
+    .describe("created, or skipped because TimescaleDB is not installed"),
+  view: z.string().optional().describe("Schema-qualified view name"),
+  sql: z.string().optional().describe("SQL that was applied"),
+  helper_file: z
+    .string()
+    .optional()
+    .describe("Typed query helper, relative to the application directory"),
+} as const;
+
+type OutputSchema = {
+  success: boolean;
+  message: string;
+  status?: "created" | "skipped";
+  view?: string;
+  sql?: string;
+  helper_file?: string;
+};
+
+type Aggregate = { fn: (typeof aggregateFunctions)[number]; column?: string };
+
+type ColumnRow = {
+  table_schema: string;
+  column_name: string;
+  data_type: string;
+};
+
+// How postgres.js returns each type to the app
+const tsTypes: Record<string, string> = {
+  "timestamp with time zone": "Date",
+  "timestamp without time zone": "Date",
+  date: "Date",
+  smallint: "number",
+  integer: "number",
+  real: "number",
+  "double precision": "number",
+  bigint: "string",
+  numeric: "string",
+  boolean: "boolean",
+  text: "string",
+  "character varying": "string",
+  uuid: "string",
+};
+
+function aggregateAlias({ fn, column }: Aggregate): string {
+  return column ? `${fn}_${column}` : fn;
+}
+
+function aggregateType({ fn }: Aggregate, columnType: string): string {
+  const isFloat = columnType === "double precision" || columnType === "real";
+  switch (fn) {
+    case "count":
+      // bigint
+      return "string";
+    case "sum":
+    case "avg":
+      // numeric or bigint, unless the input column is a float
+      return isFloat ? "number" : "string";
+    default:
+      return tsTypes[columnType] ?? "unknown";
+  }
+}
+
+function toPascalCase(name: string): string {
+  return name
+    .split("_")
+    .filter(Boolean)
+    .map((w) => w.charAt(0).toUpperCase() + w.slice(1))
+    .join("");
+}
+
+function renderHelper(
+  schema: string,
+  view: string,
+  fields: Array<[string, string]>,
+): string {
+  const typeName = `${toPascalCase(view)}Row`;
+  return `import { sql } from "drizzle-orm";
+
+import { db } from "~/server/db";
+
+// Row of the ${schema}.${view} continuous aggregate
+export type ${typeName} = {
+${fields.map(([name, type]) => `  ${name}: ${type};`).join("\n")}
+};
+
+/**
+ * Read ${view} buckets in [from, to), oldest first
+ */
+export async function get${toPascalCase(view)}(
+  from: Date,
+  to: Date,
+): Promise<${typeName}[]> {
+  const rows = await db.execute(sql\`
+    SELECT * FROM ${schema}.${view}
+    WHERE bucket >= \${from} AND bucket < \${to}
+    ORDER BY bucket
+  \`);
+  return rows as unknown as ${typeName}[];
+}
+`;
+}
+
+export const addContinuousAggregateFactory: ApiFactory<
+  ServerContext,
+  typeof inputSchema,
+  typeof outputSchema
+> = () => {
+  return {
+    name: "add_continuous_aggregate",
+    config: {
+      title: "Add Continuous Aggregate",
+      description:
+        "📊 Create a TimescaleDB continuous aggregate (an incrementally refreshed materialized view) that buckets a hypertable hourly or daily, with a refresh policy and a typed query helper for dashboards. Convert the table with make_hypertable first.",
+      inputSchema,
+      outputSchema,
+    },
+    fn: async (input): Promise<OutputSchema> => {
+      const resolved = await resolveDatabaseUrl(input);
+      if ("error" in resolved) {
+        return { success: false, message: resolved.error };
+      }
+
+      for (const agg of input.aggregates) {
+        if (agg.fn !== "count" && !agg.column) {
+          return {
+            success: false,
+            message: `The ${agg.fn} aggregate needs a column`,
+          };
+        }
+      }
+
+      const [schemaName, tableName = ""] = input.hypertable.includes(".")
+        ? input.hypertable.split(".")
+        : [undefined, input.hypertable];
+      const bucket = buckets[input.bucket];
+      const viewName = input.view_name ?? `${tableName}_${input.bucket}`;
+
+      // Check the helper path before creating anything, so an existing file
+      // isn't overwritten and the view isn't left without its helper
+      const appDir = resolve(process.cwd(), input.application_directory);
+      const dbDir = join(appDir, "src", "server", "db");
+      const helperFile =
+        input.write_helper && existsSync(dbDir)
+          ? join("src", "server", "db", `${viewName}.ts`)
+          : undefined;
+      if (helperFile && existsSync(join(appDir, helperFile))) {
+        return {
+          success: false,
+          message: `${helperFile} already exists. Choose another view_name or set write_helper to false.`,
+        };
+      }
+
+      const sql = postgres(resolved.databaseUrl, {
+        max: 1,
+        connect_timeout: 10,
+        onnotice: () => {},
+      });
+
+      try {
+        const extension = await sql`
+          SELECT 1 FROM pg_extension WHERE extname = 'timescaledb'
+        `;
+        if (extension.length === 0) {
+          return {
+            success: true,
+            message:
+              "TimescaleDB is not installed, so no continuous aggregate was created",
+            status: "skipped",
+          };
+        }
+
+        const hypertables = await sql<{ hypertable_schema: string }[]>`
+          SELECT hypertable_schema
+          FROM timescaledb_information.hypertables
+          WHERE hypertable_name = ${tableName}
+            AND ${
+              schemaName
+                ? sql`hypertable_schema = ${schemaName}`
+                : sql`hypertable_schema = ANY (current_schemas(false))`
+            }
+        `;
+        const schema = hypertables[0]?.hypertable_schema;
+        if (!schema) {
+          return {
+            success: false,
+            message: `${input.hypertable} is not a hypertable. Convert it with make_hypertable first.`,
+          };
+        }
+
+        const columns = await sql<ColumnRow[]>`
+          SELECT table_schema, column_name, data_type
+          FROM information_schema.columns
+          WHERE table_schema = ${schema} AND table_name = ${tableName}
+        `;
+        const columnTypes = new Map(
+          columns.map((c) => [c.column_name, c.data_type]),
+        );
+        const referenced = [
+          input.time_column,
+          ...input.group_by,
+          ...input.aggregates.flatMap((a) => (a.column ? [a.column] : [])),
+        ];
+        const missing = referenced.filter((c) => !columnTypes.has(c));
+        if (missing.length > 0) {
+          return {
+            success: false,
+            message: `${schema}.${tableName} has no column(s): ${missing.join(", ")}`,
+          };
+        }
+
+        const selectList = [
+          `time_bucket(INTERVAL '${bucket.width}', ${input.time_column}) AS bucket`,
+          ...input.group_by,
+          ...input.aggregates.map(
+            (a) => `${a.fn}(${a.column ?? "*"}) AS ${aggregateAlias(a)}`,
+          ),
+        ];
+        const createView = `CREATE MATERIALIZED VIEW ${schema}.${viewName}
+WITH (timescaledb.continuous) AS
+SELECT ${selectList.join(",\n       ")}
+FROM ${schema}.${tableName}
+GROUP BY ${["bucket", ...input.group_by].join(", ")}
+WITH NO DATA`;
+        const addPolicy = `SELECT add_continuous_aggregate_policy('${schema}.${viewName}',
+  start_offset => INTERVAL '${bucket.startOffset}',
+  end_offset => INTERVAL '${bucket.width}',
+  schedule_interval => INTERVAL '${bucket.schedule}')`;
+
+        // Continuous aggregates can't be created inside a transaction, so
+        // drop the view by hand if its policy can't be added
+        await sql.unsafe(createView);
+        try {
+          await sql.unsafe(addPolicy);
+        } catch (err) {
+          await sql
+            .unsafe(`DROP MATERIALIZED VIEW IF EXISTS ${schema}.${viewName}`)
+            .catch(() => {});
+          throw err;
+        }
+
+        if (helperFile) {
+          const fields: Array<[string, string]> = [
+            ["bucket", "Date"],
+            ...input.group_by.map((c): [string, string] => [
+              c,
+              tsTypes[columnTypes.get(c) ?? ""] ?? "unknown",
+            ]),
+            ...input.aggregates.map((a): [string, string] => [
+              aggregateAlias(a),
+              aggregateType(a, columnTypes.get(a.column ?? "") ?? ""),
+            ]),
+          ];
+          await writeFile(
+            join(appDir, helperFile),
+            renderHelper(schema, viewName, fields),
+          );
+        }
+
+        return {
+          success: true,
+          message: `Created continuous aggregate ${schema}.${viewName} (${input.bucket}), refreshed every ${bucket.schedule}. It fills in on the first policy run; call refresh_continuous_aggregate to backfill older data now.`,
+          status: "created",
+          view: `${schema}.${viewName}`,
+          sql: `${createView};\n\n${addPolicy};`,
+          ...(helperFile ? { helper_file: helperFile } : {}),
+        };
+      } catch (err) {
+        const error = err as Error;
+        return {
+          success: false,
+          message: `Failed to create continuous aggregate: ${error.message}`,
+        };
+      } finally {
+        await sql.end();
+      }
+    },
+  };
+};
@@ -0,0 +1,76 @@
+import ts from "typescript";
+import { describe, expect, it } from "vitest";
+import { corsHelper, wireCorsIntoRoute } from "./addCors.js";
+
+type CorsModule = {
+  corsPreflight: (req: { headers: Headers }) => Response;
+};
+
+/**
+ * Compile the generated src/server/cors.ts and load it with the given
+ * CORS_ALLOWED_ORIGINS, standing in Response for next/server's NextResponse
+ */
+function loadCorsHelper(allowedOrigins: string): CorsModule {
+  const { outputText } = ts.transpileModule(corsHelper, {
+    compilerOptions: { module: ts.ModuleKind.CommonJS },
+  });
+  const exports = {};
+  const require = () => ({ NextResponse: Response });
+  const process = { env: { CORS_ALLOWED_ORIGINS: allowedOrigins } };
+  new Function("require", "exports", "process", outputText)(
+    require,
+    exports,
+    process,
+  );
+  return exports as CorsModule;
+}
+
+function preflight(allowedOrigins: string, origin: string): Headers {
+  const req = { headers: new Headers({ origin }) };
+  return loadCorsHelper(allowedOrigins).corsPreflight(req).headers;
+}
+
+describe("cors helper", () => {
+  it("should allow credentials for a listed origin", () => {
+    const origin = "https://app.example.com";
+    const headers = preflight(origin, origin);
+    expect(headers.get("access-control-allow-origin")).toBe(origin);
+    expect(headers.get("access-control-allow-credentials")).toBe("true");
+  });
+
+  it("should never allow credentials for a wildcard match", () => {
+    const headers = preflight("*", "https://evil.example");
+    expect(headers.get("access-control-allow-origin")).toBe("*");
+    expect(headers.get("access-control-allow-credentials")).toBeNull();
+  });
+
+  it("should send no CORS headers for other origins", () => {
+    const headers = preflight(
+      "https://app.example.com",
+      "https://evil.example",
+    );
+    expect(headers.get("access-control-allow-origin")).toBeNull();
+  });
+});
+
+describe("wireCorsIntoRoute", () => {
+  it("should wrap the create-t3-app handler export", () => {
+    const source = `import { fetchRequestHandler } from "@trpc/server/adapters/fetch";
+
+const handler = (req: Request) => fetchRequestHandler({ req });
+
+export { handler as GET, handler as POST };
+`;
+    const wired = wireCorsIntoRoute(source);
+    expect(wired).toContain(
+      'import { corsPreflight, withCors } from "~/server/cors";',
+    );
+    expect(wired).toContain(
+      "export { corsHandler as GET, corsHandler as POST, corsPreflight as OPTIONS };",
+    );
+  });
+
+  it("should return null for other export shapes", () => {
+    expect(wireCorsIntoRoute("export async function GET() {}")).toBeNull();
+  });
+});
@@ -0,0 +1,253 @@
+import { existsSync } from "node:fs";
+import { mkdir, readdir, readFile, writeFile } from "node:fs/promises";
+import { dirname, join, relative, resolve } from "node:path";
+import type { ApiFactory } from "@tigerdata/mcp-boilerplate";
+import { z } from "zod";
+import { updateEnvFile } from "../../lib/env.js";
+import type { ServerContext } from "../../types.js";
+
+const inputSchema = {
+  application_directory: z
+    .string()
+    .default(".")
+    .describe("Path to the application directory"),
+  allowed_origins: z
+    .array(
+      z
+        .string()
+        .regex(
+          /^(\*|https?:\/\/[^/\s]+)$/,
+          "Origins look like http://localhost:5173 (no path), or * for any (without cookies)",
+        ),
+    )
+    .min(1)
+    .describe(
+      "Origins allowed to call the API, e.g. http://localhost:5173 or https://app.example.com. Listed origins may send cookies; * allows any origin but never with credentials",
+    ),
+  env_file: z
+    .string()
+    .default(".env")
+    .describe(
+      "Env file to write CORS_ALLOWED_ORIGINS to, relative to application_directory",
+    ),
+} as const;
+
+const outputSchema = {
+  success: z.boolean().describe("Whether CORS was configured"),
+  message: z.string().describe("Status message"),
+  files: z
+    .array(z.string())
+    .optional()
+    .describe(
+      "Files created or modified, relative to the application directory",
+    ),
+  unwired_routes: z
+    .array(z.string())
+    .optional()
+    .describe(
+      "API route files that couldn't be wrapped automatically; wrap their handlers with withCors and export OPTIONS = corsPreflight",
+    ),
+} as const;
+
+type OutputSchema = {
+  success: boolean;
+  message: string;
+  files?: string[];
+  unwired_routes?: string[];
+};
+
+const corsHelperPath = join("src", "server", "cors.ts");
+const corsImport = 'import { corsPreflight, withCors } from "~/server/cors";';
+
+export const corsHelper = `import { type NextRequest, NextResponse } from "next/server";
+
+// Comma-separated origins allowed to call the API (CORS_ALLOWED_ORIGINS)
+const allowedOrigins = (process.env.CORS_ALLOWED_ORIGINS ?? "")
+  .split(",")
+  .map((origin) => origin.trim())
+  .filter(Boolean);
+
+const allowedMethodsAndHeaders = {
+  "Access-Control-Allow-Methods": "GET, POST, PUT, PATCH, DELETE, OPTIONS",
+  "Access-Control-Allow-Headers":
+    "Content-Type, Authorization, trpc-accept, x-trpc-source",
+};
+
+function corsHeaders(origin: string | null): Record<string, string> {
+  if (!origin) {
+    return {};
+  }
+  // Only listed origins may send cookies; * would let any site make
+  // credentialed requests to the API (including auth routes)
+  if (allowedOrigins.includes(origin)) {
+    return {
+      "Access-Control-Allow-Origin": origin,
+      "Access-Control-Allow-Credentials": "true",
+      ...allowedMethodsAndHeaders,
+      Vary: "Origin",
+    };
+  }
+  if (allowedOrigins.includes("*")) {
+    return {
+      "Access-Control-Allow-Origin": "*",
+      ...allowedMethodsAndHeaders,
+    };
+  }
+  return {};
+}
+
+/**
+ * Wrap a route handler so its responses carry CORS headers for allowed origins
+ */
+export function withCors<Args extends unknown[]>(
+  handler: (req: NextRequest, ...args: Args) => Response | Promise<Response>,
+) {
+  return async (req: NextRequest, ...args: Args) => {
+    const res = await handler(req, ...args);
+    // Copy so headers are mutable even for fetch or redirect responses
+    const response = new Response(res.body, res);
+    for (const [key, value] of Object.entries(
+      corsHeaders(req.headers.get("origin")),
+    )) {
+      response.headers.set(key, value);
+    }
+    return response;
+  };
+}
+
+/**
+ * Answer a CORS preflight request; export as OPTIONS from API routes
+ */
+export function corsPreflight(req: NextRequest) {
+  return new NextResponse(null, {
+    status: 204,
+    headers: corsHeaders(req.headers.get("origin")),
+  });
+}
+`;
+
+// export { handler as GET, handler as POST }; as generated by create-t3-app
+const handlerExportPattern =
+  /^export\s*\{\s*(\w+)\s+as\s+\w+(?:\s*,\s*\1\s+as\s+\w+)*\s*,?\s*\};?[ \t]*$/m;
+
+/**
+ * Wrap a route file's handler export with withCors and add an OPTIONS
+ * export. Returns null if the file doesn't use the single-handler shape.
+ */
+export function wireCorsIntoRoute(source: string): string | null {
+  const match = handlerExportPattern.exec(source);
+  if (!match) {
+    return null;
+  }
+
+  const handler = match[1] ?? "handler";
+  const methods = [...match[0].matchAll(/\bas\s+(\w+)/g)].map((m) => m[1]);
+  const exports = [
+    `const corsHandler = withCors(${handler});`,
+    "",
+    `export { ${[
+      ...methods.map((method) => `corsHandler as ${method}`),
+      "corsPreflight as OPTIONS",
+    ].join(", ")} };`,
+  ].join("\n");
+
+  // Put the import after the last existing import
+  const imports = [
+    ...source.matchAll(/^import[\s\S]*?from\s+["'][^"']+["'];?$/gm),
+  ];
+  const lastImport = imports.at(-1);
+  const withExports = source.replace(match[0], exports);
+  if (!lastImport || lastImport.index === undefined) {
+    return `${corsImport}\n${withExports}`;
+  }
+  const insertAt = lastImport.index + lastImport[0].length;
+  return `${withExports.slice(0, insertAt)}\n${corsImport}${withExports.slice(insertAt)}`;
+}
+
+const routeFileNames = new Set(["route.ts", "route.js"]);
+
+async function findRouteFiles(dir: string): Promise<string[]> {
+  if (!existsSync(dir)) {
+    return [];
+  }
+  const entries = await readdir(dir, { recursive: true, withFileTypes: true });
+  return entries
+    .filter((e) => e.isFile() && routeFileNames.has(e.name))
+    .map((e) => join(e.parentPath, e.name))
+    .sort();
+}
+
+export const addCorsFactory: ApiFactory<
+  ServerContext,
+  typeof inputSchema,
+  typeof outputSchema
+> = () => {
+  return {
+    name: "add_cors",
+    config: {
+      title: "Add CORS",
+      description:
+        "🌐 Allow other origins (e.g. a separate frontend or mobile web app) to call the Next.js API routes. Writes a src/server/cors.ts helper, wraps App Router API route handlers with it, adds OPTIONS preflight handlers, and sets CORS_ALLOWED_ORIGINS in the env file.",
+      inputSchema,
+      outputSchema,
+    },
+    fn: async ({
+      application_directory,
+      allowed_origins,
+      env_file,
+    }): Promise<OutputSchema> => {
+      const appDir = resolve(process.cwd(), application_directory);
+      const apiDir = join(appDir, "src", "app", "api");
+
+      if (!existsSync(join(appDir, "src", "app"))) {
+        return {
+          success: false,
+          message: `No src/app directory in ${appDir}. add_cors only supports the Next.js App Router.`,
+        };
+      }
+
+      const files: string[] = [];
+      const unwired: string[] = [];
+
+      try {
+        const helperPath = join(appDir, corsHelperPath);
+        if (!existsSync(helperPath)) {
+          await mkdir(dirname(helperPath), { recursive: true });
+          await writeFile(helperPath, corsHelper);
+          files.push(corsHelperPath);
+        }
+
+        for (const routeFile of await findRouteFiles(apiDir)) {
+          const source = await readFile(routeFile, "utf-8");
+          if (source.includes("~/server/cors")) continue;
+
+          const wired = wireCorsIntoRoute(source);
+          if (wired === null) {
+            unwired.push(relative(appDir, routeFile));
+            continue;
+          }
+          await writeFile(routeFile, wired);
+          files.push(relative(appDir, routeFile));
+        }
+
+        await updateEnvFile(join(appDir, env_file), {
+          CORS_ALLOWED_ORIGINS: allowed_origins.join(","),
+        });
+        files.push(env_file);
+      } catch (err) {
+        const error = err as Error;
+        return {
+          success: false,
+          message: `Failed to add CORS: ${error.message}`,
+        };
+      }
+
+      return {
+        success: true,
+        message: `Allowed ${allowed_origins.join(", ")} to call the API. Also set CORS_ALLOWED_ORIGINS on your deployment.${unwired.length > 0 ? ` ${unwired.length} route(s) need withCors added by hand.` : ""}`,
+        files,
+        ...(unwired.length > 0 ? { unwired_routes: unwired } : {}),
+      };
+    },
+  };
+};
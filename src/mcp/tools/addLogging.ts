@@ -0,0 +1,221 @@
+import { existsSync } from "node:fs";
+import { mkdir, readFile, writeFile } from "node:fs/promises";
+import { dirname, join, resolve } from "node:path";
+import type { ApiFactory } from "@tigerdata/mcp-boilerplate";
+import { z } from "zod";
+import type { ServerContext } from "../../types.js";
+
+const inputSchema = {
+  application_directory: z
+    .string()
+    .default(".")
+    .describe("Path to the application directory"),
+  request_id_header: z
+    .string()
+    .regex(
+      /^[a-z0-9-]+$/,
+      "Header names are lowercase letters, digits, and hyphens",
+    )
+    .default("x-request-id")
+    .describe("Correlation id header, reused from the request when present"),
+} as const;
+
+const outputSchema = {
+  success: z.boolean().describe("Whether logging was added"),
+  message: z.string().describe("Status message"),
+  files: z
+    .array(z.string())
+    .optional()
+    .describe("Files created, relative to the application directory"),
+  skipped: z
+    .array(z.string())
+    .optional()
+    .describe("Files that already existed and were left unchanged"),
+} as const;
+
+type OutputSchema = {
+  success: boolean;
+  message: string;
+  files?: string[];
+  skipped?: string[];
+};
+
+function renderLogger(header: string): string {
+  return `import type { NextRequest } from "next/server";
+
+export const requestIdHeader = "${header}";
+
+type Level = "debug" | "info" | "warn" | "error";
+
+/**
+ * Write one structured JSON log line to stdout
+ */
+function write(level: Level, msg: string, fields: Record<string, unknown>) {
+  console.log(
+    JSON.stringify({ time: new Date().toISOString(), level, msg, ...fields }),
+  );
+}
+
+export const logger = {
+  debug: (msg: string, fields: Record<string, unknown> = {}) =>
+    write("debug", msg, fields),
+  info: (msg: string, fields: Record<string, unknown> = {}) =>
+    write("info", msg, fields),
+  warn: (msg: string, fields: Record<string, unknown> = {}) =>
+    write("warn", msg, fields),
+  error: (msg: string, fields: Record<string, unknown> = {}) =>
+    write("error", msg, fields),
+};
+
+/**
+ * Wrap an API route handler to log method, path, status, and duration
+ */
+export function withRequestLogging<Args extends unknown[]>(
+  handler: (req: NextRequest, ...args: Args) => Response | Promise<Response>,
+) {
+  return async (req: NextRequest, ...args: Args) => {
+    const start = performance.now();
+    const fields = {
+      method: req.method,
+      path: req.nextUrl.pathname,
+      request_id: req.headers.get(requestIdHeader),
+    };
+    try {
+      const res = await handler(req, ...args);
+      logger.info("request completed", {
+        ...fields,
+        status: res.status,
+        duration_ms: Math.round(performance.now() - start),
+      });
+      return res;
+    } catch (err) {
+      logger.error("request failed", {
+        ...fields,
+        status: 500,
+        duration_ms: Math.round(performance.now() - start),
+        error: err instanceof Error ? err.message : String(err),
+      });
+      throw err;
+    }
+  };
+}
+`;
+}
+
+function renderMiddleware(fn: "middleware" | "proxy"): string {
+  return `import { type NextRequest, NextResponse } from "next/server";
+
+import { logger, requestIdHeader } from "~/server/logger";
+
+/**
+ * Give every request a correlation id and log it. Middleware runs before the
+ * route, so status and duration are logged by withRequestLogging instead.
+ */
+export function ${fn}(req: NextRequest) {
+  const requestId = req.headers.get(requestIdHeader) ?? crypto.randomUUID();
+
+  const headers = new Headers(req.headers);
+  headers.set(requestIdHeader, requestId);
+
+  logger.info("request received", {
+    method: req.method,
+    path: req.nextUrl.pathname,
+    request_id: requestId,
+  });
+
+  const res = NextResponse.next({ request: { headers } });
+  res.headers.set(requestIdHeader, requestId);
+  return res;
+}
+
+export const config = {
+  // Skip static assets and Next.js internals
+  matcher: ["/((?!_next/static|_next/image|favicon.ico).*)"],
+};
+`;
+}
+
+/**
+ * Next.js 16 renamed middleware.ts to proxy.ts; older versions need middleware
+ */
+async function getMiddlewareName(
+  appDir: string,
+): Promise<"middleware" | "proxy"> {
+  try {
+    const pkg = JSON.parse(
+      await readFile(join(appDir, "package.json"), "utf-8"),
+    ) as { dependencies?: Record<string, string> };
+    const major = Number(pkg.dependencies?.next?.match(/\d+/)?.[0]);
+    return major >= 16 ? "proxy" : "middleware";
+  } catch {
+    return "middleware";
+  }
+}
+
+export const addLoggingFactory: ApiFactory<
+  ServerContext,
+  typeof inputSchema,
+  typeof outputSchema
+> = () => {
+  return {
+    name: "add_logging",
+    config: {
+      title: "Add Request Logging",
+      description:
+        "📋 Add structured JSON request logging to a Next.js app: src/proxy.ts (src/middleware.ts before Next.js 16) assigns a correlation id header and logs each request, and src/server/logger.ts provides a logger plus withRequestLogging to log status and duration from API route handlers.",
+      inputSchema,
+      outputSchema,
+    },
+    fn: async ({
+      application_directory,
+      request_id_header,
+    }): Promise<OutputSchema> => {
+      const appDir = resolve(process.cwd(), application_directory);
+
+      if (!existsSync(join(appDir, "src", "app"))) {
+        return {
+          success: false,
+          message: `No src/app directory in ${appDir}. add_logging only supports the Next.js App Router.`,
+        };
+      }
+
+      const middlewareName = await getMiddlewareName(appDir);
+      const middlewarePath = join("src", `${middlewareName}.ts`);
+      const planned: Array<[string, string]> = [
+        [join("src", "server", "logger.ts"), renderLogger(request_id_header)],
+        [middlewarePath, renderMiddleware(middlewareName)],
+      ];
+
+      const files: string[] = [];
+      const skipped: string[] = [];
+      try {
+        for (const [relPath, content] of planned) {
+          const path = join(appDir, relPath);
+          if (existsSync(path)) {
+            skipped.push(relPath);
+            continue;
+          }
+          await mkdir(dirname(path), { recursive: true });
+          await writeFile(path, content);
+          files.push(relPath);
+        }
+      } catch (err) {
+        const error = err as Error;
+        return {
+          success: false,
+          message: `Failed to add logging: ${error.message}`,
+        };
+      }
+
+      const notes = skipped.includes(middlewarePath)
+        ? ` ${middlewarePath} already exists, so add the ${request_id_header} header and logger.info call to it by hand.`
+        : "";
+      return {
+        success: true,
+        message: `Added request logging. Wrap API route handlers with withRequestLogging from ~/server/logger to log status and duration.${notes}`,
+        files,
+        ...(skipped.length > 0 ? { skipped } : {}),
+      };
+    },
+  };
+};
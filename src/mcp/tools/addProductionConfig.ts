@@ -0,0 +1,173 @@
+import { existsSync } from "node:fs";
+import { readFile, writeFile } from "node:fs/promises";
+import { join, resolve } from "node:path";
+import type { ApiFactory } from "@tigerdata/mcp-boilerplate";
+import { z } from "zod";
+import { runCommand } from "../../lib/exec.js";
+import type { ServerContext } from "../../types.js";
+
+const inputSchema = {
+  application_directory: z
+    .string()
+    .default(".")
+    .describe("Path to the application directory"),
+} as const;
+
+const outputSchema = {
+  success: z.boolean().describe("Whether the production config was applied"),
+  message: z.string().describe("Status message"),
+  files: z
+    .array(z.string())
+    .optional()
+    .describe(
+      "Files created or modified, relative to the application directory",
+    ),
+} as const;
+
+type OutputSchema = {
+  success: boolean;
+  message: string;
+  files?: string[];
+};
+
+const nextConfigFiles = ["next.config.js", "next.config.mjs"];
+
+const productionOptions = `  output: "standalone",
+  images: {
+    formats: ["image/avif", "image/webp"],
+  },
+`;
+
+const dockerignore = `# Dependencies and build output are rebuilt in the image
+node_modules
+.next
+out
+coverage
+
+# Local env files hold secrets; pass them at runtime instead
+.env
+.env*.local
+
+# VCS, editor, and tooling files
+.git
+.gitignore
+.vscode
+.idea
+*.log
+Dockerfile*
+.dockerignore
+README.md
+`;
+
+/**
+ * Add standalone output and image optimization to the config object in a
+ * next.config.js. Returns null if the file doesn't have the expected shape.
+ */
+export function addProductionOptions(source: string): string | null {
+  const emptyConfig = /const config = \{\s*\};/;
+  if (emptyConfig.test(source)) {
+    return source.replace(
+      emptyConfig,
+      `const config = {\n${productionOptions}};`,
+    );
+  }
+
+  const configStart = /const config = \{\n/;
+  if (configStart.test(source)) {
+    return source.replace(
+      configStart,
+      `const config = {\n${productionOptions}`,
+    );
+  }
+
+  return null;
+}
+
+export const addProductionConfigFactory: ApiFactory<
+  ServerContext,
+  typeof inputSchema,
+  typeof outputSchema
+> = () => {
+  return {
+    name: "add_production_config",
+    config: {
+      title: "Add Production Config",
+      description:
+        "📦 Prepare a Next.js app for container deployment: set output: 'standalone' and AVIF/WebP image formats in next.config.js, and add a .dockerignore that keeps node_modules, build output, and env files out of the image.",
+      inputSchema,
+      outputSchema,
+    },
+    fn: async ({ application_directory }): Promise<OutputSchema> => {
+      const appDir = resolve(process.cwd(), application_directory);
+
+      const configFile = nextConfigFiles.find((f) =>
+        existsSync(join(appDir, f)),
+      );
+      if (!configFile) {
+        return {
+          success: false,
+          message: `No next.config.js or next.config.mjs found in ${appDir}`,
+        };
+      }
+
+      const files: string[] = [];
+      const notes: string[] = [];
+      const configPath = join(appDir, configFile);
+
+      try {
+        const source = await readFile(configPath, "utf-8");
+        if (/\boutput\s*:/.test(source)) {
+          notes.push(`${configFile} already sets output; left unchanged.`);
+        } else {
+          const updated = addProductionOptions(source);
+          if (updated === null) {
+            return {
+              success: false,
+              message: `Couldn't find 'const config = {' in ${configFile}. Add output: "standalone" to the Next.js config by hand.`,
+            };
+          }
+
+          await writeFile(configPath, updated);
+
+          // Make sure the config still loads before the dev server trips on it
+          const check = await runCommand("node", ["--check", configPath], {
+            cwd: appDir,
+          });
+          if (check.exitCode !== 0) {
+            await writeFile(configPath, source);
+            return {
+              success: false,
+              message: `Updated ${configFile} failed a syntax check, so it was restored:\n${check.stderr}`,
+            };
+          }
+          files.push(configFile);
+        }
+
+        const dockerignorePath = join(appDir, ".dockerignore");
+        if (existsSync(dockerignorePath)) {
+          notes.push(".dockerignore already exists; left unchanged.");
+        } else {
+          await writeFile(dockerignorePath, dockerignore);
+          files.push(".dockerignore");
+        }
+      } catch (err) {
+        const error = err as Error;
+        return {
+          success: false,
+          message: `Failed to add production config: ${error.message}`,
+        };
+      }
+
+      return {
+        success: true,
+        message: [
+          files.length > 0
+            ? `Updated ${files.join(", ")}. \`npm run build\` now writes a self-contained server to .next/standalone (copy .next/static and public alongside it).`
+            : "Nothing to change.",
+          ...notes,
+        ].join(" "),
+        files,
+      };
+    },
+  };
+};
@@ -0,0 +1,64 @@
+import { describe, expect, it } from "vitest";
+import { addOpenGraphDefaults, missingContentColumns } from "./addSeo.js";
+
+const schema = `import { pgTableCreator } from "drizzle-orm/pg-core";
+
+export const createTable = pgTableCreator((name) => \`app_\${name}\`);
+
+export const posts = createTable("post", (d) => ({
+  id: d.integer().primaryKey().generatedByDefaultAsIdentity(),
+  slug: d.varchar({ length: 256 }).notNull(),
+  published: d.boolean().default(false).notNull(),
+}));
+
+export const users = createTable("user", (d) => ({
+  updatedAt: d.timestamp({ withTimezone: true }),
+}));
+`;
+
+describe("missingContentColumns", () => {
+  it("should accept columns defined on the table", () => {
+    expect(
+      missingContentColumns(schema, {
+        table: "posts",
+        slug_column: "slug",
+        published_column: "published",
+      }),
+    ).toEqual([]);
+  });
+
+  it("should not look at columns of other tables", () => {
+    expect(
+      missingContentColumns(schema, {
+        table: "posts",
+        slug_column: "slug",
+        updated_column: "updatedAt",
+      }),
+    ).toEqual(["updatedAt"]);
+  });
+
+  it("should return null for an unknown table", () => {
+    expect(
+      missingContentColumns(schema, { table: "articles", slug_column: "slug" }),
+    ).toBeNull();
+  });
+});
+
+describe("addOpenGraphDefaults", () => {
+  it("should add defaults with the layout's indentation", () => {
+    const layout = `export const metadata: Metadata = {\n\ttitle: "App",\n};\n`;
+    const result = addOpenGraphDefaults(layout, "my-app");
+    expect(result).toContain(
+      '\tmetadataBase: new URL(process.env.NEXT_PUBLIC_SITE_URL ?? "http://localhost:3000"),\n',
+    );
+    expect(result).toContain(
+      '\topenGraph: { type: "website", siteName: "my-app" },\n\ttwitter',
+    );
+    expect(result).toMatch(/\n\ttitle: "App",\n\};\n$/);
+  });
+
+  it("should return null without a metadata export", () => {
+    const layout = "export default function Layout() {}\n";
+    expect(addOpenGraphDefaults(layout, "a")).toBeNull();
+  });
+});
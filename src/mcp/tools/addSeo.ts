@@ -0,0 +1,383 @@
+import { existsSync } from "node:fs";
+import { mkdir, readFile, writeFile } from "node:fs/promises";
+import { basename, dirname, join, resolve } from "node:path";
+import type { ApiFactory } from "@tigerdata/mcp-boilerplate";
+import { z } from "zod";
+import { updateEnvFile } from "../../lib/env.js";
+import type { ServerContext } from "../../types.js";
+import { collectRoutes, type Route } from "./listRoutes.js";
+
+// Drizzle table and column property names as written in schema.ts
+const identifier = z
+  .string()
+  .regex(/^[A-Za-z_][A-Za-z0-9_]*$/, "Must be a schema.ts property name");
+
+const inputSchema = {
+  application_directory: z
+    .string()
+    .default(".")
+    .describe("Path to the application directory"),
+  site_url: z
+    .string()
+    .url()
+    .default("http://localhost:3000")
+    .describe(
+      "Public base URL of the site, written to NEXT_PUBLIC_SITE_URL (set the production URL on your deployment)",
+    ),
+  env_file: z
+    .string()
+    .default(".env")
+    .describe(
+      "Env file to write NEXT_PUBLIC_SITE_URL to, relative to application_directory",
+    ),
+  content: z
+    .object({
+      table: identifier.describe(
+        "Drizzle table exported from src/server/db/schema.ts",
+      ),
+      slug_column: identifier
+        .default("slug")
+        .describe("Column holding the URL slug"),
+      published_column: identifier
+        .optional()
+        .describe("Boolean column; only rows where it is true are listed"),
+      updated_column: identifier
+        .optional()
+        .describe("Timestamp column used as lastModified"),
+      url_prefix: z
+        .string()
+        .regex(/^\/[a-z0-9\-/]*$/, "URL prefix looks like /blog")
+        .optional()
+        .describe("Path the rows are served under (default: /<table>)"),
+    })
+    .optional()
+    .describe(
+      "Database table whose rows (e.g. published posts) get sitemap entries, queried through the app's Drizzle db",
+    ),
+} as const;
+
+const outputSchema = {
+  success: z.boolean().describe("Whether SEO files were created"),
+  message: z.string().describe("Status message"),
+  files: z
+    .array(z.string())
+    .optional()
+    .describe(
+      "Files created or modified, relative to the application directory",
+    ),
+  sitemap_urls: z
+    .array(z.string())
+    .optional()
+    .describe("Page paths listed in the sitemap"),
+} as const;
+
+type OutputSchema = {
+  success: boolean;
+  message: string;
+  files?: string[];
+  sitemap_urls?: string[];
+};
+
+type ContentSource = {
+  table: string;
+  slug_column: string;
+  published_column?: string | undefined;
+  updated_column?: string | undefined;
+  url_prefix?: string | undefined;
+};
+
+const schemaPath = join("src", "server", "db", "schema.ts");
+const layoutPath = join("src", "app", "layout.tsx");
+const metadataHelperPath = join("src", "lib", "metadata.ts");
+
+/**
+ * Pages worth listing in a sitemap: public and without dynamic segments
+ */
+export function sitemapPaths(routes: Route[]): string[] {
+  return routes
+    .filter((r) => r.kind === "page" && !r.protected && !r.url.includes("["))
+    .map((r) => r.url)
+    .sort();
+}
+
+/**
+ * Columns referenced by a content source that schema.ts doesn't define on
+ * the table, or null if the table itself isn't exported
+ */
+export function missingContentColumns(
+  schema: string,
+  content: ContentSource,
+): string[] | null {
+  const start = schema.search(
+    new RegExp(`export const ${content.table}\\s*=`),
+  );
+  if (start === -1) {
+    return null;
+  }
+  // The table definition runs until the next top-level export
+  const next = schema.indexOf("\nexport ", start + 1);
+  const definition = schema.slice(start, next === -1 ? undefined : next);
+  return [
+    content.slug_column,
+    content.published_column,
+    content.updated_column,
+  ].filter(
+    (column): column is string =>
+      column !== undefined &&
+      !new RegExp(`\\b${column}\\s*:`).test(definition),
+  );
+}
+
+function renderSitemap(paths: string[], content?: ContentSource): string {
+  if (!content) {
+    return `import type { MetadataRoute } from "next";
+
+const siteUrl = process.env.NEXT_PUBLIC_SITE_URL ?? "http://localhost:3000";
+
+// Public pages found when the sitemap was generated
+const paths = ${JSON.stringify(paths)};
+
+export default function sitemap(): MetadataRoute.Sitemap {
+  return paths.map((path) => ({
+    url: new URL(path, siteUrl).toString(),
+    lastModified: new Date(),
+  }));
+}
+`;
+  }
+
+  const { table, slug_column, published_column, updated_column } = content;
+  const prefix = content.url_prefix ?? `/${table}`;
+  const select = [
+    `slug: ${table}.${slug_column}`,
+    ...(updated_column ? [`updatedAt: ${table}.${updated_column}`] : []),
+  ].join(", ");
+  const where = published_column
+    ? `\n    .where(eq(${table}.${published_column}, true))`
+    : "";
+  const lastModified = updated_column
+    ? "row.updatedAt ?? new Date()"
+    : "new Date()";
+
+  return `${published_column ? 'import { eq } from "drizzle-orm";\n' : ""}import type { MetadataRoute } from "next";
+
+import { db } from "~/server/db";
+import { ${table} } from "~/server/db/schema";
+
+const siteUrl = process.env.NEXT_PUBLIC_SITE_URL ?? "http://localhost:3000";
+
+// Public pages found when the sitemap was generated
+const paths = ${JSON.stringify(paths)};
+
+// Regenerate hourly so new ${table} rows show up without a redeploy
+export const revalidate = 3600;
+
+export default async function sitemap(): Promise<MetadataRoute.Sitemap> {
+  const rows = await db
+    .select({ ${select} })
+    .from(${table})${where};
+
+  const pages = paths.map((path) => ({
+    url: new URL(path, siteUrl).toString(),
+    lastModified: new Date(),
+  }));
+  const entries = rows.map((row) => ({
+    url: new URL(\`${prefix}/\${row.slug}\`, siteUrl).toString(),
+    lastModified: ${lastModified},
+  }));
+  return [...pages, ...entries];
+}
+`;
+}
+
+const metadataHelper = `import type { Metadata } from "next";
+
+/**
+ * Metadata for a page: title, description, canonical URL, and matching
+ * Open Graph and Twitter cards. Paths resolve against metadataBase from the
+ * root layout.
+ *
+ *   export const metadata = pageMetadata({ title: "Pricing", path: "/pricing" });
+ */
+export function pageMetadata({
+  title,
+  description,
+  path,
+  image,
+}: {
+  title: string;
+  description?: string;
+  path: string;
+  image?: string;
+}): Metadata {
+  const images = image ? [{ url: image }] : undefined;
+  return {
+    title,
+    description,
+    alternates: { canonical: path },
+    openGraph: { title, description, url: path, images },
+    twitter: { title, description, images },
+  };
+}
+`;
+
+/**
+ * Add metadataBase and Open Graph/Twitter defaults to the root layout's
+ * metadata export. Returns null if there's no object literal to extend.
+ */
+export function addOpenGraphDefaults(
+  layout: string,
+  siteName: string,
+): string | null {
+  const match =
+    /export const metadata(?::\s*Metadata)?\s*=\s*\{\n([ \t]*)/.exec(layout);
+  if (!match) {
+    return null;
+  }
+
+  const indent = match[1] ?? "  ";
+  const defaults = [
+    `metadataBase: new URL(process.env.NEXT_PUBLIC_SITE_URL ?? "http://localhost:3000"),`,
+    `openGraph: { type: "website", siteName: ${JSON.stringify(siteName)} },`,
+    `twitter: { card: "summary_large_image" },`,
+  ]
+    .map((line) => `${indent}${line}\n`)
+    .join("");
+  const at = match.index + match[0].length - indent.length;
+  return `${layout.slice(0, at)}${defaults}${layout.slice(at)}`;
+}
+
+const robots = `import type { MetadataRoute } from "next";
+
+const siteUrl = process.env.NEXT_PUBLIC_SITE_URL ?? "http://localhost:3000";
+
+export default function robots(): MetadataRoute.Robots {
+  return {
+    rules: { userAgent: "*", allow: "/", disallow: "/api/" },
+    sitemap: new URL("/sitemap.xml", siteUrl).toString(),
+  };
+}
+`;
+
+export const addSeoFactory: ApiFactory<
+  ServerContext,
+  typeof inputSchema,
+  typeof outputSchema
+> = () => {
+  return {
+    name: "add_seo",
+    config: {
+      title: "Add SEO",
+      description:
+        "🔎 Add SEO scaffolding: src/app/sitemap.ts listing the app's public pages (plus one entry per row of a content table such as published posts, if given), src/app/robots.ts, a pageMetadata() helper in src/lib/metadata.ts for per-page titles and Open Graph cards, and metadataBase/Open Graph defaults in the root layout. The base URL comes from NEXT_PUBLIC_SITE_URL.",
+      inputSchema,
+      outputSchema,
+    },
+    fn: async ({
+      application_directory,
+      site_url,
+      env_file,
+      content,
+    }): Promise<OutputSchema> => {
+      const appDir = resolve(process.cwd(), application_directory);
+      const routesDir = join(appDir, "src", "app");
+
+      if (!existsSync(routesDir)) {
+        return {
+          success: false,
+          message: `No src/app directory in ${appDir}. add_seo only supports the Next.js App Router.`,
+        };
+      }
+
+      const existing = ["sitemap.ts", "robots.ts"].filter((f) =>
+        existsSync(join(routesDir, f)),
+      );
+      if (existing.length > 0) {
+        return {
+          success: false,
+          message: `src/app/${existing.join(" and src/app/")} already exists`,
+        };
+      }
+
+      if (content) {
+        const schemaFile = join(appDir, schemaPath);
+        const schema = existsSync(schemaFile)
+          ? await readFile(schemaFile, "utf-8")
+          : "";
+        const missing = missingContentColumns(schema, content);
+        if (missing === null) {
+          return {
+            success: false,
+            message: `${schemaPath} doesn't export a table named ${content.table}`,
+          };
+        }
+        if (missing.length > 0) {
+          return {
+            success: false,
+            message: `${content.table} in ${schemaPath} has no ${missing.join(", ")} column(s)`,
+          };
+        }
+      }
+
+      const files: string[] = [];
+      const notes: string[] = [];
+      let paths: string[];
+      try {
+        const routes: Route[] = [];
+        await collectRoutes(appDir, routesDir, [], routes);
+        paths = sitemapPaths(routes);
+
+        await writeFile(
+          join(routesDir, "sitemap.ts"),
+          renderSitemap(paths, content),
+        );
+        await writeFile(join(routesDir, "robots.ts"), robots);
+        files.push(join("src", "app", "sitemap.ts"));
+        files.push(join("src", "app", "robots.ts"));
+
+        const helperFile = join(appDir, metadataHelperPath);
+        if (existsSync(helperFile)) {
+          notes.push(`${metadataHelperPath} already exists, left unchanged.`);
+        } else {
+          await mkdir(dirname(helperFile), { recursive: true });
+          await writeFile(helperFile, metadataHelper);
+          files.push(metadataHelperPath);
+        }
+
+        const layoutFile = join(appDir, layoutPath);
+        const layout = existsSync(layoutFile)
+          ? await readFile(layoutFile, "utf-8")
+          : "";
+        const withDefaults = addOpenGraphDefaults(layout, basename(appDir));
+        if (layout.includes("metadataBase")) {
+          notes.push(`${layoutPath} already sets metadataBase, left unchanged.`);
+        } else if (withDefaults === null) {
+          notes.push(
+            `Add metadataBase and Open Graph defaults to the metadata export in ${layoutPath} by hand.`,
+          );
+        } else {
+          await writeFile(layoutFile, withDefaults);
+          files.push(layoutPath);
+        }
+
+        await updateEnvFile(join(appDir, env_file), {
+          NEXT_PUBLIC_SITE_URL: site_url,
+        });
+        files.push(env_file);
+      } catch (err) {
+        const error = err as Error;
+        return {
+          success: false,
+          message: `Failed to add SEO files: ${error.message}`,
+        };
+      }
+
+      return {
+        success: true,
+        message: `Added a sitemap with ${paths.length} public page(s)${content ? ` and one entry per ${content.table} row` : ""}, robots.txt, and a pageMetadata() helper. Add NEXT_PUBLIC_SITE_URL to src/env.js and set it to the production URL on your deployment.${notes.length > 0 ? ` ${notes.join(" ")}` : ""}`,
+        files,
+        sitemap_urls: paths,
+      };
+    },
+  };
+};
@@ -0,0 +1,112 @@
+import type { ApiFactory } from "@tigerdata/mcp-boilerplate";
+import postgres from "postgres";
+import { z } from "zod";
+import { databaseUrlInputs, resolveDatabaseUrl } from "../../lib/database.js";
+import type { ServerContext } from "../../types.js";
+
+const inputSchema = {
+  ...databaseUrlInputs,
+} as const;
+
+const outputSchema = {
+  success: z.boolean().describe("Whether the database is reachable"),
+  message: z.string().describe("Status message"),
+  env_file: z
+    .string()
+    .optional()
+    .describe("Env file DATABASE_URL was read from"),
+  latency_ms: z
+    .number()
+    .optional()
+    .describe("Round-trip time of SELECT 1 in milliseconds"),
+  tables: z
+    .array(z.string())
+    .optional()
+    .describe("Tables visible on the connection's search_path (schema.table)"),
+  timescaledb: z
+    .boolean()
+    .optional()
+    .describe("Whether the TimescaleDB extension is installed"),
+  timescaledb_version: z
+    .string()
+    .optional()
+    .describe("Installed TimescaleDB version"),
+} as const;
+
+type OutputSchema = {
+  success: boolean;
+  message: string;
+  env_file?: string | undefined;
+  latency_ms?: number;
+  tables?: string[];
+  timescaledb?: boolean;
+  timescaledb_version?: string | undefined;
+};
+
+type TableRow = {
+  table_schema: string;
+  table_name: string;
+};
+
+export const checkDatabaseFactory: ApiFactory<
+  ServerContext,
+  typeof inputSchema,
+  typeof outputSchema
+> = () => {
+  return {
+    name: "check_database",
+    config: {
+      title: "Check Database",
+      description:
+        "🩺 Check that an app's database is reachable. Runs SELECT 1, reports latency, lists tables, and reports TimescaleDB availability. Use when the app fails with connection errors.",
+      inputSchema,
+      outputSchema,
+    },
+    fn: async (input): Promise<OutputSchema> => {
+      const resolved = await resolveDatabaseUrl(input);
+      if ("error" in resolved) {
+        return { success: false, message: resolved.error };
+      }
+      const { databaseUrl, envFile } = resolved;
+
+      const sql = postgres(databaseUrl, { max: 1, connect_timeout: 10 });
+
+      try {
+        const start = performance.now();
+        await sql`SELECT 1`;
+        const latency_ms = Math.round(performance.now() - start);
+
+        const tables = await sql<TableRow[]>`
+          SELECT table_schema, table_name
+          FROM information_schema.tables
+          WHERE table_schema = ANY (current_schemas(false))
+          ORDER BY table_schema, table_name
+        `;
+
+        const extension = await sql<{ extversion: string }[]>`
+          SELECT extversion FROM pg_extension WHERE extname = 'timescaledb'
+        `;
+        const timescaledbVersion = extension[0]?.extversion;
+
+        return {
+          success: true,
+          message: `Connected in ${latency_ms}ms. Found ${tables.length} table(s).`,
+          env_file: envFile,
+          latency_ms,
+          tables: tables.map((t) => `${t.table_schema}.${t.table_name}`),
+          timescaledb: timescaledbVersion !== undefined,
+          timescaledb_version: timescaledbVersion,
+        };
+      } catch (err) {
+        const error = err as Error;
+        return {
+          success: false,
+          message: `Failed to connect to database: ${error.message}`,
+          env_file: envFile,
+        };
+      } finally {
+        await sql.end();
+      }
+    },
+  };
+};
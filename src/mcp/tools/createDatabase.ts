@@ -1,13 +1,54 @@
-import { exec } from "node:child_process";
-import { promisify } from "node:util";
 import type { ApiFactory } from "@tigerdata/mcp-boilerplate";
 import { z } from "zod";
+import { getExitCode } from "../../lib/exec.js";
+import {
+  buildCreateServiceArgs,
+  computeConfigs,
+  createService,
+  describeCompute,
+  isValidCompute,
+  serviceAddons,
+} from "../../lib/tiger.js";
 import type { ServerContext } from "../../types.js";
 
-const execAsync = promisify(exec);
+const cpuValues = computeConfigs.map((c) => c.cpu) as [string, ...string[]];
+const memoryValues = computeConfigs.map((c) => c.memory) as [
+  string,
+  ...string[],
+];
+const supportedCompute = computeConfigs
+  .map((c) => `${c.cpu}/${c.memory}`)
+  .join(", ");
 
 const inputSchema = {
   name: z.string().optional().describe("Database name (default: app-db)"),
+  cpu: z
+    .enum(cpuValues)
+    .default("shared")
+    .describe(
+      "CPU in millicores, or 'shared' for the free tier (default: shared). Must pair with memory.",
+    ),
+  memory: z
+    .enum(memoryValues)
+    .default("shared")
+    .describe(
+      `Memory in GB, or 'shared' for the free tier (default: shared). Supported cpu/memory pairs: ${supportedCompute}`,
+    ),
+  region: z
+    .string()
+    .regex(/^[a-z0-9-]+$/, "Region must be a cloud region code like us-east-1")
+    .optional()
+    .describe("Cloud region for the service (default: chosen by Tiger Cloud)"),
+  addons: z
+    .array(z.enum(serviceAddons))
+    .default([...serviceAddons])
+    .describe("Service addons to enable (default: time-series, ai)"),
+  dry_run: z
+    .boolean()
+    .default(false)
+    .describe(
+      "Preview the tiger command without creating a service (no free-tier quota used)",
+    ),
 } as const;
 
 const outputSchema = {
@@ -15,69 +56,102 @@ const outputSchema = {
     .boolean()
     .describe("Whether the database was created successfully"),
   service_id: z.string().optional().describe("The Tiger Cloud service ID"),
+  compute: z
+    .string()
+    .optional()
+    .describe("The compute tier the service was created with"),
+  dry_run: z
+    .boolean()
+    .optional()
+    .describe("True if this was a dry run and no service was created"),
+  command: z
+    .string()
+    .optional()
+    .describe("The tiger command that was (or would be) run"),
   error: z.string().optional().describe("Error message if creation failed"),
+  exit_code: z
+    .number()
+    .optional()
+    .describe(
+      "Exit code of the failed tiger command (127 means tiger is not installed)",
+    ),
 } as const;
 
 type OutputSchema = {
   success: boolean;
   service_id?: string;
+  compute?: string;
+  dry_run?: boolean;
+  command?: string;
   error?: string;
+  exit_code?: number | undefined;
 };
 
 export const createDatabaseFactory: ApiFactory<
   ServerContext,
   typeof inputSchema,
   typeof outputSchema
-> = () => {
+> = ({ tiger }) => {
   return {
     name: "create_database",
     config: {
       title: "Create Database",
       description:
-        "🗄️ Set up any database - PostgreSQL on Tiger Cloud (default, FREE). Auto-configures with schema, migrations, and connection handling. Use for any database request.",
+        "🗄️ Set up any database - PostgreSQL on Tiger Cloud (default, FREE). Auto-configures with schema, migrations, and connection handling. Use for any database request. Only set cpu/memory/region when the user asks for a paid or region-pinned instance.",
       inputSchema,
       outputSchema,
     },
-    fn: async ({ name }): Promise<OutputSchema> => {
-      const dbName = name || "app-db";
+    fn: async ({
+      name,
+      cpu,
+      memory,
+      region,
+      addons,
+      dry_run,
+    }): Promise<OutputSchema> => {
+      if (!isValidCompute(cpu, memory)) {
+        return {
+          success: false,
+          error: `Unsupported cpu/memory combination ${cpu}/${memory}. Supported pairs: ${supportedCompute}`,
+        };
+      }
 
-      const cmdArgs = [
-        "tiger",
-        "service",
-        "create",
-        "--name",
-        dbName,
-        "--cpu",
-        "shared",
-        "--memory",
-        "shared",
-        "--addons",
-        "time-series,ai",
-        "--no-wait",
-        "-o",
-        "json",
-      ];
+      const serviceOptions = {
+        name: name || "app-db",
+        cpu,
+        memory,
+        addons,
+        region,
+      };
+      const compute = describeCompute(cpu, memory);
+      const command = ["tiger", ...buildCreateServiceArgs(serviceOptions)].join(
+        " ",
+      );
 
-      try {
-        const { stdout, stderr } = await execAsync(cmdArgs.join(" "));
-        const result = JSON.parse(stdout) as { service_id?: string };
+      if (dry_run) {
+        return {
+          success: true,
+          compute,
+          dry_run: true,
+          command,
+        };
+      }
 
-        if (!result.service_id) {
-          return {
-            success: false,
-            error: `No service_id in response: ${stdout}${stderr}`,
-          };
-        }
+      try {
+        const serviceId = await createService(tiger, serviceOptions);
 
         return {
           success: true,
-          service_id: result.service_id,
+          service_id: serviceId,
+          compute,
+          command,
         };
       } catch (err) {
-        const error = err as Error & { stdout?: string; stderr?: string };
+        const error = err as Error;
         return {
           success: false,
-          error: `Failed to create database: ${error.message}\n${error.stdout || ""}${error.stderr || ""}`,
+          error: error.message,
+          exit_code: getExitCode(err),
         };
       }
     },
@@ -0,0 +1,45 @@
+import { describe, expect, it } from "vitest";
+import { toSchemaName, wireAuthSchema } from "./createWebApp.js";
+
+const authConfig = `import { betterAuth } from "better-auth";
+import { drizzleAdapter } from "better-auth/adapters/drizzle";
+
+import { db } from "~/server/db";
+
+export const auth = betterAuth({
+\tdatabase: drizzleAdapter(db, {
+\t\tprovider: "pg",
+\t}),
+});
+`;
+
+describe("wireAuthSchema", () => {
+  it("should pass the schema to drizzleAdapter", () => {
+    const wired = wireAuthSchema(authConfig);
+    expect(wired).toContain(
+      '\tdatabase: drizzleAdapter(db, {\n\t\tschema,\n\t\tprovider: "pg",',
+    );
+    expect(wired).toContain(
+      'import { db } from "~/server/db";\nimport * as schema from "~/server/db/schema";\n',
+    );
+  });
+
+  it("should leave an already wired config unchanged", () => {
+    const wired = wireAuthSchema(authConfig) ?? "";
+    expect(wireAuthSchema(wired)).toBe(wired);
+  });
+
+  it("should return null without a drizzleAdapter call", () => {
+    expect(wireAuthSchema("export const auth = betterAuth({});\n")).toBeNull();
+  });
+});
+
+describe("toSchemaName", () => {
+  it("should lowercase and replace invalid characters", () => {
+    expect(toSchemaName("My-App")).toBe("my_app");
+  });
+
+  it("should prefix names that don't start with a letter", () => {
+    expect(toSchemaName("2fa")).toBe("app_2fa");
+  });
+});
@@ -1,17 +1,36 @@
-import { exec } from "node:child_process";
-import { unlink } from "node:fs/promises";
-import { join } from "node:path";
-import { promisify } from "node:util";
+import { existsSync } from "node:fs";
+import { mkdir, readFile, unlink, writeFile } from "node:fs/promises";
+import { join, relative, resolve } from "node:path";
 import type { ApiFactory } from "@tigerdata/mcp-boilerplate";
 import { z } from "zod";
-import { writeAppTemplates } from "../../lib/templates.js";
+import { getExitCode, runCommandChecked } from "../../lib/exec.js";
+import {
+  validateAppTemplateDir,
+  writeAppTemplates,
+} from "../../lib/templates.js";
+import {
+  findWorkspace,
+  getWorkspaceAppsDir,
+  registerWorkspaceDir,
+} from "../../lib/workspace.js";
 import type { ServerContext } from "../../types.js";
-
-const execAsync = promisify(exec);
+import { setupAppSchemaFactory } from "./setupAppSchema.js";
 
 const inputSchema = {
   app_name: z.string().describe("Application name"),
   use_auth: z.boolean().default(false).describe("Enable authentication"),
+  with_auth: z
+    .enum(["better-auth"])
+    .optional()
+    .describe(
+      "Scaffold this auth provider (implies use_auth) and wire the Drizzle schema into its adapter",
+    ),
+  with_database: z
+    .boolean()
+    .default(false)
+    .describe(
+      "Run setup_app_schema on the new app once it is scaffolded, so DATABASE_URL is wired in one call. Requires service_id; tables still need npm run db:push",
+    ),
   product_brief: z
     .string()
     .optional()
@@ -22,25 +41,144 @@ const inputSchema = {
     .describe(
       "Features deferred to later that may affect architectural decisions",
     ),
+  description: z
+    .string()
+    .optional()
+    .describe("One-line project description for package.json"),
+  author: z
+    .string()
+    .optional()
+    .describe("Project author for package.json (default: git user.name)"),
+  license: z
+    .string()
+    .optional()
+    .describe("SPDX license identifier for package.json, e.g. MIT"),
+  template_dir: z
+    .string()
+    .optional()
+    .describe(
+      "Custom app template directory to copy into the app instead of the built-in templates. Files are rendered with Handlebars using app_name, use_auth, product_brief, future_features, description, author, license, year (current year), and framework (Next.js)",
+    ),
+  service_id: z
+    .string()
+    .optional()
+    .describe(
+      "Tiger Cloud service ID from create_database, used by with_database",
+    ),
 } as const;
 
 const outputSchema = {
   success: z.boolean().describe("Whether the app was created successfully"),
   message: z.string().describe("Status message"),
   path: z.string().optional().describe("Path to created app"),
+  workspace_root: z
+    .string()
+    .optional()
+    .describe(
+      "Root of the existing monorepo the app was added to, if one was detected",
+    ),
+  exit_code: z
+    .number()
+    .optional()
+    .describe(
+      "Exit code of the failed command (127 means the command was not found)",
+    ),
+  schema_name: z
+    .string()
+    .optional()
+    .describe(
+      "Database schema created by setup_app_schema, if with_database was set",
+    ),
+  database_message: z
+    .string()
+    .optional()
+    .describe("Result of setup_app_schema, if with_database was set"),
+  auth_message: z
+    .string()
+    .optional()
+    .describe("Result of wiring auth, if with_auth was set"),
 } as const;
 
+/**
+ * Set metadata fields in the scaffolded package.json, leaving others as-is
+ */
+async function updatePackageInfo(
+  appDir: string,
+  info: Record<string, string | undefined>,
+): Promise<void> {
+  const fields = Object.fromEntries(
+    Object.entries(info).filter(([, value]) => value !== undefined),
+  );
+  if (Object.keys(fields).length === 0) {
+    return;
+  }
+
+  const pkgPath = join(appDir, "package.json");
+  const pkg = JSON.parse(await readFile(pkgPath, "utf-8")) as Record<
+    string,
+    unknown
+  >;
+  await writeFile(
+    pkgPath,
+    `${JSON.stringify({ ...pkg, ...fields }, null, 2)}\n`,
+  );
+}
+
+/**
+ * Schema and user name for an app: lowercase with underscores, starting with
+ * a letter, as setup_app_schema requires
+ */
+export function toSchemaName(appName: string): string {
+  const name = appName
+    .toLowerCase()
+    .replace(/[^a-z0-9_]+/g, "_")
+    .replace(/^_+|_+$/g, "");
+  return /^[a-z]/.test(name) ? name : `app_${name}`;
+}
+
+const authConfigPath = join("src", "server", "better-auth", "config.ts");
+
+/**
+ * Pass the app's Drizzle schema to Better Auth's drizzleAdapter so it can
+ * find the auth tables. Returns null if the adapter call isn't found.
+ */
+export function wireAuthSchema(config: string): string | null {
+  const adapter = /drizzleAdapter\(db, \{\n([ \t]*)/.exec(config);
+  if (!adapter) {
+    return null;
+  }
+  if (/drizzleAdapter\(db, \{[^}]*\bschema\b/.test(config)) {
+    return config;
+  }
+
+  const indent = adapter[1] ?? "  ";
+  const at = adapter.index + adapter[0].length;
+  const wired = `${config.slice(0, at)}schema,\n${indent}${config.slice(at)}`;
+
+  // "~/" imports sort last, so the schema import goes after the others
+  const imports = [...wired.matchAll(/^import [^;]*;\n/gm)];
+  const last = imports[imports.length - 1];
+  const end = last ? (last.index ?? 0) + last[0].length : 0;
+  const schemaImport = 'import * as schema from "~/server/db/schema";\n';
+  return `${wired.slice(0, end)}${schemaImport}${wired.slice(end)}`;
+}
+
 type OutputSchema = {
   success: boolean;
   message: string;
   path?: string;
+  workspace_root?: string;
+  exit_code?: number | undefined;
+  schema_name?: string | undefined;
+  database_message?: string;
+  auth_message?: string;
 };
 
 export const createWebAppFactory: ApiFactory<
   ServerContext,
   typeof inputSchema,
   typeof outputSchema
-> = () => {
+> = (context) => {
   return {
     name: "create_web_app",
     config: {
@@ -53,15 +191,62 @@ export const createWebAppFactory: ApiFactory<
     fn: async ({
       app_name,
       use_auth,
+      with_auth,
+      with_database,
       product_brief,
       future_features,
+      description,
+      author,
+      license,
+      template_dir,
+      service_id,
     }): Promise<OutputSchema> => {
       const appName = app_name;
+      const useAuth = use_auth || with_auth !== undefined;
+
+      if (with_database && !service_id) {
+        return {
+          success: false,
+          message:
+            "with_database needs a service_id. Create one with create_database first.",
+        };
+      }
 
+      const templateDir = template_dir
+        ? resolve(process.cwd(), template_dir)
+        : undefined;
+      if (templateDir) {
+        try {
+          const missing = validateAppTemplateDir(templateDir);
+          if (missing.length > 0) {
+            return {
+              success: false,
+              message: `Template directory ${templateDir} is missing required files: ${missing.join(", ")}`,
+            };
+          }
+        } catch (err) {
+          const error = err as Error;
+          return { success: false, message: error.message };
+        }
+      }
+
+      // Undoes the workspace config change if a later step fails
+      let unregister: (() => Promise<void>) | null = null;
       try {
+        // Inside an existing monorepo, add the app under apps/ (or packages/)
+        // and install from the workspace root instead of creating a sibling
+        // project with its own lockfile
+        const workspace = await findWorkspace(process.cwd());
+        const appsDir = workspace ? getWorkspaceAppsDir(workspace) : "";
+        let parentDir = process.cwd();
+        if (workspace) {
+          parentDir = join(workspace.root, appsDir);
+          await mkdir(parentDir, { recursive: true });
+        }
+        const appDir = join(parentDir, appName);
+
         // Create T3 app
         const t3Args = [
-          "npx",
           "create-t3-app@latest",
           appName,
           "--noInstall", //avoids dependency conflicts that could result
@@ -75,42 +260,116 @@ export const createWebAppFactory: ApiFactory<
           "--appRouter",
           "--biome",
         ];
-        if (use_auth) {
+        if (useAuth) {
           t3Args.push("--betterAuth");
         }
 
-        await execAsync(t3Args.join(" "));
+        await runCommandChecked("npx", t3Args, { cwd: parentDir });
 
         // Remove start-database script if it exists
         try {
-          await unlink(join(appName, "start-database.sh"));
+          await unlink(join(appDir, "start-database.sh"));
         } catch {
           // Ignore if file doesn't exist
         }
 
+        await updatePackageInfo(appDir, { description, author, license });
+
         // Copy app templates (globals.css, etc.)
-        await writeAppTemplates(appName, {
-          app_name: appName,
-          use_auth,
-          product_brief,
-          future_features,
-        });
+        await writeAppTemplates(
+          appDir,
+          {
+            app_name: appName,
+            use_auth: useAuth,
+            product_brief,
+            future_features,
+          },
+          templateDir,
+        );
 
         // Upgrade dependencies (except drizzle-orm which has compatibility issues)
-        await execAsync(
-          `cd ${appName} && npx npm-check-updates -u --reject drizzle-orm && npm install`,
+        await runCommandChecked(
+          "npx",
+          ["npm-check-updates", "-u", "--reject", "drizzle-orm"],
+          { cwd: appDir },
         );
+        if (workspace) {
+          // Register the apps directory only once the app exists, since the
+          // workspace install needs it
+          unregister = await registerWorkspaceDir(workspace, appsDir);
+          await runCommandChecked(workspace.packageManager, ["install"], {
+            cwd: workspace.root,
+          });
+          unregister = null;
+        } else {
+          await runCommandChecked("npm", ["install"], { cwd: appDir });
+        }
+
+        const path = relative(process.cwd(), appDir) || ".";
+        const message = workspace
+          ? `Created app '${appName}' in the ${workspace.packageManager} workspace at ${workspace.root}`
+          : `Created app '${appName}'`;
+
+        // The app exists either way, so auth and database failures (e.g. the
+        // service isn't READY yet) are reported without failing the call.
+        // Neither step creates tables: setup_app_schema only creates the
+        // schema and user, so the app's tables, auth ones included, still
+        // need `npm run db:push`.
+        let auth: Pick<OutputSchema, "auth_message"> = {};
+        if (with_auth) {
+          const configFile = join(appDir, authConfigPath);
+          const config = existsSync(configFile)
+            ? await readFile(configFile, "utf-8")
+            : "";
+          const wired = wireAuthSchema(config);
+          if (wired === null) {
+            auth = {
+              auth_message: `Couldn't find drizzleAdapter in ${authConfigPath}; pass the Drizzle schema to it by hand.`,
+            };
+          } else {
+            await writeFile(configFile, wired);
+            auth = {
+              auth_message: `Wired the Drizzle schema into Better Auth in ${authConfigPath}.`,
+            };
+          }
+        }
+
+        let database: Pick<OutputSchema, "schema_name" | "database_message"> =
+          {};
+        if (with_database && service_id) {
+          const setupAppSchema = setupAppSchemaFactory(context);
+          const schema = await setupAppSchema.fn(
+            z.object(setupAppSchema.config.inputSchema).parse({
+              application_directory: appDir,
+              service_id,
+              app_name: toSchemaName(appName),
+            }),
+          );
+          database = schema.success
+            ? {
+                schema_name: schema.schema_name,
+                database_message: `${schema.message} Run npm run db:push to create the app's tables.`,
+              }
+            : {
+                database_message: `${schema.message} Run setup_app_schema once the service is READY.`,
+              };
+        }
 
         return {
           success: true,
-          message: `Created app '${appName}'`,
-          path: appName,
+          message,
+          path,
+          ...(workspace ? { workspace_root: workspace.root } : {}),
+          ...auth,
+          ...database,
         };
       } catch (err) {
-        const error = err as Error & { stderr?: string };
+        await unregister?.().catch(() => {});
+        const error = err as Error;
         return {
           success: false,
-          message: `Failed to create app: ${error.message}\n${error.stderr || ""}`,
+          message: `Failed to create app: ${error.message}`,
+          exit_code: getExitCode(err),
         };
       }
     },
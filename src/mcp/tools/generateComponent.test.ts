@@ -0,0 +1,87 @@
+import { mkdirSync, rmSync } from "node:fs";
+import { tmpdir } from "node:os";
+import { join } from "node:path";
+import { afterEach, beforeEach, describe, expect, it } from "vitest";
+import { z } from "zod";
+import { context } from "../serverInfo.js";
+import {
+  generateComponentFactory,
+  renderComponent,
+} from "./generateComponent.js";
+
+describe("renderComponent", () => {
+  it("should render text props, flags, and callbacks", () => {
+    const source = renderComponent(
+      "TaskCard",
+      [
+        { name: "title", type: "string", optional: false },
+        { name: "done", type: "boolean", optional: true },
+        { name: "onDelete", type: "() => void", optional: true },
+      ],
+      true,
+    );
+
+    expect(source).toMatch(/^"use client";/);
+    expect(source).toContain(
+      "export function TaskCard({ title, done, onDelete, className }: TaskCardProps)",
+    );
+    expect(source).toContain("Task Card</h3>");
+    expect(source).toContain("{title}</p>");
+    expect(source).toContain("data-done={done || undefined}");
+    expect(source).toContain("onClick={onDelete}");
+    expect(source).not.toContain("TODO");
+  });
+
+  it("should not render event handlers in a server component", () => {
+    const source = renderComponent(
+      "TaskCard",
+      [{ name: "onDelete", type: "() => void", optional: true }],
+      false,
+    );
+
+    expect(source).not.toContain('"use client"');
+    expect(source).not.toContain("onClick");
+    expect(source).toContain("  onDelete?: () => void;");
+    expect(source).toContain("export function TaskCard({ className }");
+  });
+});
+
+describe("generate_component", () => {
+  let appDir: string;
+
+  beforeEach(() => {
+    appDir = join(
+      tmpdir(),
+      `component-test-${Date.now()}-${Math.random().toString(36).slice(2)}`,
+    );
+    mkdirSync(appDir, { recursive: true });
+  });
+
+  afterEach(() => {
+    rmSync(appDir, { recursive: true, force: true });
+  });
+
+  it("should refuse directories outside the app", async () => {
+    const result = await generateComponentFactory(context).fn({
+      application_directory: appDir,
+      name: "TaskCard",
+      props: [],
+      client: false,
+      directory: "../elsewhere",
+    });
+
+    expect(result.success).toBe(false);
+    expect(result.message).toContain("outside the application directory");
+  });
+
+  it("should reject a className prop", () => {
+    const { inputSchema } = generateComponentFactory(context).config;
+    const parsed = z.object(inputSchema).safeParse({
+      name: "TaskCard",
+      props: [{ name: "className", type: "string" }],
+    });
+
+    expect(parsed.success).toBe(false);
+    expect(parsed.error?.issues[0]?.message).toContain("className");
+  });
+});
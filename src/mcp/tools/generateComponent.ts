@@ -0,0 +1,242 @@
+import { existsSync } from "node:fs";
+import { mkdir, writeFile } from "node:fs/promises";
+import { dirname, isAbsolute, join, relative, resolve } from "node:path";
+import type { ApiFactory } from "@tigerdata/mcp-boilerplate";
+import { z } from "zod";
+import type { ServerContext } from "../../types.js";
+
+const inputSchema = {
+  application_directory: z
+    .string()
+    .default(".")
+    .describe("Path to the application directory"),
+  name: z
+    .string()
+    .regex(
+      /^[A-Z][A-Za-z0-9]*$/,
+      "Component name must be PascalCase, e.g. TaskCard",
+    )
+    .describe("Component name in PascalCase (e.g. TaskCard)"),
+  props: z
+    .array(
+      z.object({
+        name: z
+          .string()
+          .regex(/^[a-z][A-Za-z0-9]*$/, "Prop names must be camelCase")
+          .refine(
+            (name) => name !== "className",
+            "className is added to every component already",
+          )
+          .describe("Prop name (camelCase)"),
+        type: z
+          .string()
+          .describe("TypeScript type of the prop (e.g. string, () => void)"),
+        optional: z.boolean().default(false).describe("Whether it's optional"),
+      }),
+    )
+    .default([])
+    .describe("Props the component accepts"),
+  client: z
+    .boolean()
+    .default(false)
+    .describe(
+      "Add 'use client' (needed for hooks, state, or event handlers). on* callback props are only rendered as buttons in client components",
+    ),
+  directory: z
+    .string()
+    .default("src/components")
+    .describe("Directory for the component, relative to application_directory"),
+} as const;
+
+const outputSchema = {
+  success: z.boolean().describe("Whether the component was created"),
+  message: z.string().describe("Status message"),
+  path: z.string().optional().describe("Path to the created component file"),
+  example: z
+    .string()
+    .optional()
+    .describe("Example JSX showing how to use the component"),
+} as const;
+
+type OutputSchema = {
+  success: boolean;
+  message: string;
+  path?: string;
+  example?: string;
+};
+
+type ComponentProp = {
+  name: string;
+  type: string;
+  optional: boolean;
+};
+
+function toKebabCase(name: string): string {
+  return name.replace(/([a-z0-9])([A-Z])/g, "$1-$2").toLowerCase();
+}
+
+const textTypes = new Set(["string", "number", "React.ReactNode", "ReactNode"]);
+
+function toWords(name: string): string {
+  return name.replace(/([a-z0-9])([A-Z])/g, "$1 $2");
+}
+
+/**
+ * JSX for the props a starting component can show: text-like values as
+ * text, booleans as data attributes, and on* callbacks as buttons in client
+ * components (server components can't attach event handlers). Other props
+ * stay in the interface for the caller to wire up.
+ */
+function renderBody(
+  props: ComponentProp[],
+  client: boolean,
+): {
+  used: string[];
+  attributes: string;
+  children: string[];
+} {
+  const used: string[] = [];
+  let attributes = "";
+  const children: string[] = [];
+
+  for (const p of props) {
+    if (textTypes.has(p.type)) {
+      used.push(p.name);
+      children.push(
+        `      <p className="text-muted-foreground text-sm">{${p.name}}</p>`,
+      );
+    } else if (p.type === "boolean") {
+      used.push(p.name);
+      attributes += ` data-${toKebabCase(p.name)}={${p.name} || undefined}`;
+    } else if (client && /^on[A-Z]/.test(p.name) && p.type.includes("=>")) {
+      used.push(p.name);
+      const label = toWords(p.name.slice(2));
+      children.push(
+        [
+          "      <button",
+          '        type="button"',
+          '        className="text-primary text-sm underline-offset-4 hover:underline"',
+          `        onClick={${p.name}}`,
+          "      >",
+          `        ${label}`,
+          "      </button>",
+        ].join("\n"),
+      );
+    }
+  }
+
+  return { used, attributes, children };
+}
+
+export function renderComponent(
+  name: string,
+  props: ComponentProp[],
+  client: boolean,
+): string {
+  const propLines = props
+    .map((p) => `  ${p.name}${p.optional ? "?" : ""}: ${p.type};`)
+    .join("\n");
+  const body = renderBody(props, client);
+  const destructured = [...body.used, "className"].join(", ");
+  const children = [
+    `      <h3 className="font-semibold leading-none">${toWords(name)}</h3>`,
+    ...body.children,
+  ].join("\n");
+
+  return `${client ? '"use client";\n\n' : ""}import { cn } from "~/lib/utils";
+
+export interface ${name}Props {
+${propLines ? `${propLines}\n` : ""}  className?: string;
+}
+
+export function ${name}({ ${destructured} }: ${name}Props) {
+  return (
+    <div
+      className={cn(
+        "flex flex-col gap-2 rounded-lg border bg-card p-4 text-card-foreground",
+        className,
+      )}${body.attributes}
+    >
+${children}
+    </div>
+  );
+}
+`;
+}
+
+function exampleValue(type: string): string {
+  if (type === "string") return '""';
+  if (type === "number") return "{0}";
+  if (type === "boolean") return "{false}";
+  if (type.includes("=>")) return "{() => {}}";
+  if (type.endsWith("[]")) return "{[]}";
+  return "{undefined}";
+}
+
+function renderExample(name: string, props: ComponentProp[]): string {
+  const attrs = props
+    .filter((p) => !p.optional)
+    .map((p) => ` ${p.name}=${exampleValue(p.type)}`)
+    .join("");
+  return `<${name}${attrs} />`;
+}
+
+export const generateComponentFactory: ApiFactory<
+  ServerContext,
+  typeof inputSchema,
+  typeof outputSchema
+> = () => {
+  return {
+    name: "generate_component",
+    config: {
+      title: "Generate Component",
+      description:
+        "🧩 Scaffold a typed React component (props interface, className merging with cn(), optional 'use client') in the app's components directory. Uses shadcn/ui theme tokens, so run `npx shadcn init` first.",
+      inputSchema,
+      outputSchema,
+    },
+    fn: async ({
+      application_directory,
+      name,
+      props,
+      client,
+      directory,
+    }): Promise<OutputSchema> => {
+      const appDir = resolve(process.cwd(), application_directory);
+      const filePath = resolve(appDir, directory, `${toKebabCase(name)}.tsx`);
+      const relPath = relative(appDir, filePath);
+
+      if (relPath.startsWith("..") || isAbsolute(relPath)) {
+        return {
+          success: false,
+          message: `Directory ${directory} is outside the application directory`,
+        };
+      }
+
+      if (existsSync(filePath)) {
+        return {
+          success: false,
+          message: `${relPath} already exists. Edit it directly or choose another name.`,
+        };
+      }
+
+      try {
+        await mkdir(dirname(filePath), { recursive: true });
+        await writeFile(filePath, renderComponent(name, props, client));
+      } catch (err) {
+        const error = err as Error;
+        return {
+          success: false,
+          message: `Failed to create component: ${error.message}`,
+        };
+      }
+
+      return {
+        success: true,
+        message: `Created ${name} in ${relPath}`,
+        path: relPath,
+        example: renderExample(name, props),
+      };
+    },
+  };
+};
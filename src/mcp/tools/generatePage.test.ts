@@ -0,0 +1,61 @@
+import { existsSync, mkdirSync, rmSync } from "node:fs";
+import { tmpdir } from "node:os";
+import { join } from "node:path";
+import { afterEach, beforeEach, describe, expect, it } from "vitest";
+import { context } from "../serverInfo.js";
+import { generatePageFactory, toComponentName } from "./generatePage.js";
+
+describe("toComponentName", () => {
+  it("should build a PascalCase page name", () => {
+    expect(toComponentName("posts/[id]")).toBe("PostsIdPage");
+  });
+
+  it("should prefix names that would start with a digit", () => {
+    expect(toComponentName("2fa")).toBe("Route2faPage");
+    expect(toComponentName("settings/2fa")).toBe("Settings2faPage");
+  });
+});
+
+describe("generate_page", () => {
+  let appDir: string;
+
+  beforeEach(() => {
+    appDir = join(
+      tmpdir(),
+      `page-test-${Date.now()}-${Math.random().toString(36).slice(2)}`,
+    );
+    mkdirSync(join(appDir, "src", "app"), { recursive: true });
+  });
+
+  afterEach(() => {
+    rmSync(appDir, { recursive: true, force: true });
+  });
+
+  const generate = (route: string) =>
+    generatePageFactory(context).fn({
+      application_directory: appDir,
+      route,
+      title: "Test",
+      protected: false,
+      redirect_to: "/",
+      with_layout: false,
+      with_loading: false,
+    });
+
+  it("should refuse routes that escape src/app", async () => {
+    const result = await generate("../../x");
+
+    expect(result.success).toBe(false);
+    expect(result.message).toContain("outside src/app");
+    expect(existsSync(join(appDir, "x"))).toBe(false);
+  });
+
+  it("should create the page for a nested route", async () => {
+    const result = await generate("settings/2fa");
+
+    expect(result).toMatchObject({ success: true, url: "/settings/2fa" });
+    expect(existsSync(join(appDir, "src", "app", "settings", "2fa"))).toBe(
+      true,
+    );
+  });
+});
@@ -0,0 +1,245 @@
+import { existsSync } from "node:fs";
+import { mkdir, writeFile } from "node:fs/promises";
+import { isAbsolute, join, relative, resolve } from "node:path";
+import type { ApiFactory } from "@tigerdata/mcp-boilerplate";
+import { z } from "zod";
+import type { ServerContext } from "../../types.js";
+
+const inputSchema = {
+  application_directory: z
+    .string()
+    .default(".")
+    .describe("Path to the application directory"),
+  route: z
+    .string()
+    .regex(
+      /^\/?[a-z0-9\-_[\]().]+(\/[a-z0-9\-_[\]().]+)*\/?$/,
+      "Route must be App Router path segments, e.g. dashboard/settings or posts/[id]",
+    )
+    .refine(
+      (route) => !route.split("/").some((s) => /^\.+$/.test(s)),
+      "Route segments can't be . or ..",
+    )
+    .describe("Route path under src/app (e.g. dashboard, posts/[id])"),
+  title: z.string().describe("Page title, used for the heading and metadata"),
+  protected: z
+    .boolean()
+    .default(false)
+    .describe("Redirect signed-out users (requires the app to use auth)"),
+  redirect_to: z
+    .string()
+    .default("/")
+    .describe("Where to send signed-out users of a protected page"),
+  with_layout: z
+    .boolean()
+    .default(false)
+    .describe("Also create a layout.tsx for this route"),
+  with_loading: z
+    .boolean()
+    .default(false)
+    .describe("Also create a loading.tsx for this route"),
+} as const;
+
+const outputSchema = {
+  success: z.boolean().describe("Whether the page was created"),
+  message: z.string().describe("Status message"),
+  files: z
+    .array(z.string())
+    .optional()
+    .describe("Files created, relative to the application directory"),
+  url: z.string().optional().describe("URL path the page is served at"),
+} as const;
+
+type OutputSchema = {
+  success: boolean;
+  message: string;
+  files?: string[];
+  url?: string;
+};
+
+// Better Auth config written by create-t3-app --betterAuth
+const authConfigPath = join("src", "server", "better-auth", "config.ts");
+
+/**
+ * Component name for a route, e.g. posts/[id] -> PostsIdPage. Names that
+ * would start with a digit (2fa) are prefixed to stay valid identifiers.
+ */
+export function toComponentName(route: string): string {
+  const words = route
+    .replace(/[[\]().]/g, "")
+    .split(/[/\-_]/)
+    .filter(Boolean);
+  const name = words.map((w) => w[0]?.toUpperCase() + w.slice(1)).join("");
+  if (!name) {
+    return "IndexPage";
+  }
+  return /^\d/.test(name) ? `Route${name}Page` : `${name}Page`;
+}
+
+function toUrl(route: string): string {
+  // Route groups like (marketing) don't appear in the URL
+  const segments = route.split("/").filter((s) => s && !/^\(.*\)$/.test(s));
+  return `/${segments.join("/")}`;
+}
+
+function renderPage(
+  componentName: string,
+  title: string,
+  isProtected: boolean,
+  redirectTo: string,
+): string {
+  const escapedTitle = JSON.stringify(title);
+
+  if (!isProtected) {
+    return `import type { Metadata } from "next";
+
+export const metadata: Metadata = {
+  title: ${escapedTitle},
+};
+
+export default function ${componentName}() {
+  return (
+    <main className="container mx-auto flex flex-col gap-6 px-4 py-10">
+      <h1 className="font-bold text-3xl tracking-tight">{${escapedTitle}}</h1>
+    </main>
+  );
+}
+`;
+  }
+
+  return `import type { Metadata } from "next";
+import { headers } from "next/headers";
+import { redirect } from "next/navigation";
+import { auth } from "~/server/better-auth/config";
+
+export const metadata: Metadata = {
+  title: ${escapedTitle},
+};
+
+export default async function ${componentName}() {
+  const session = await auth.api.getSession({ headers: await headers() });
+  if (!session) {
+    redirect(${JSON.stringify(redirectTo)});
+  }
+
+  return (
+    <main className="container mx-auto flex flex-col gap-6 px-4 py-10">
+      <h1 className="font-bold text-3xl tracking-tight">{${escapedTitle}}</h1>
+    </main>
+  );
+}
+`;
+}
+
+function renderLayout(componentName: string): string {
+  return `export default function ${componentName.replace(/Page$/, "Layout")}({
+  children,
+}: Readonly<{ children: React.ReactNode }>) {
+  return <section className="min-h-screen bg-background">{children}</section>;
+}
+`;
+}
+
+function renderLoading(): string {
+  return `export default function Loading() {
+  return (
+    <main className="container mx-auto px-4 py-10">
+      <div className="h-8 w-48 animate-pulse rounded-md bg-muted" />
+    </main>
+  );
+}
+`;
+}
+
+export const generatePageFactory: ApiFactory<
+  ServerContext,
+  typeof inputSchema,
+  typeof outputSchema
+> = () => {
+  return {
+    name: "generate_page",
+    config: {
+      title: "Generate Page",
+      description:
+        "📄 Add a Next.js App Router page at src/app/<route>/page.tsx, with optional layout.tsx and loading.tsx. Set protected to redirect signed-out users using Better Auth.",
+      inputSchema,
+      outputSchema,
+    },
+    fn: async ({
+      application_directory,
+      route,
+      title,
+      protected: isProtected,
+      redirect_to,
+      with_layout,
+      with_loading,
+    }): Promise<OutputSchema> => {
+      const appDir = resolve(process.cwd(), application_directory);
+      const routePath = route.replace(/^\/+|\/+$/g, "");
+      const routesDir = join(appDir, "src", "app");
+      const routeDir = resolve(routesDir, routePath);
+      const pagePath = join(routeDir, "page.tsx");
+
+      const fromRoutes = relative(routesDir, routeDir);
+      if (fromRoutes.startsWith("..") || isAbsolute(fromRoutes)) {
+        return {
+          success: false,
+          message: `Route ${route} is outside src/app`,
+        };
+      }
+
+      if (!existsSync(routesDir)) {
+        return {
+          success: false,
+          message: `No src/app directory in ${appDir}. generate_page only supports the Next.js App Router.`,
+        };
+      }
+
+      if (existsSync(pagePath)) {
+        return {
+          success: false,
+          message: `${relative(appDir, pagePath)} already exists`,
+        };
+      }
+
+      if (isProtected && !existsSync(join(appDir, authConfigPath))) {
+        return {
+          success: false,
+          message: `Protected pages need Better Auth, but ${authConfigPath} was not found. Create the app with use_auth or set protected to false.`,
+        };
+      }
+
+      const componentName = toComponentName(routePath);
+      const files: Array<[string, string]> = [
+        [pagePath, renderPage(componentName, title, isProtected, redirect_to)],
+      ];
+      if (with_layout && !existsSync(join(routeDir, "layout.tsx"))) {
+        files.push([join(routeDir, "layout.tsx"), renderLayout(componentName)]);
+      }
+      if (with_loading && !existsSync(join(routeDir, "loading.tsx"))) {
+        files.push([join(routeDir, "loading.tsx"), renderLoading()]);
+      }
+
+      try {
+        await mkdir(routeDir, { recursive: true });
+        for (const [path, content] of files) {
+          await writeFile(path, content);
+        }
+      } catch (err) {
+        const error = err as Error;
+        return {
+          success: false,
+          message: `Failed to create page: ${error.message}`,
+        };
+      }
+
+      const url = toUrl(routePath);
+      return {
+        success: true,
+        message: `Created page '${title}' at ${url}`,
+        files: files.map(([path]) => relative(appDir, path)),
+        url,
+      };
+    },
+  };
+};
@@ -0,0 +1,63 @@
+import { describe, expect, it } from "vitest";
+import { context } from "../serverInfo.js";
+import {
+  describeTools,
+  getApiFactories,
+  getToolProfile,
+  withUniqueToolNames,
+} from "./index.js";
+import { openAppFactory } from "./openApp.js";
+
+describe("withUniqueToolNames", () => {
+  it("should register distinct tool names", () => {
+    const [openApp] = withUniqueToolNames([openAppFactory]);
+    expect(openApp?.(context).name).toBe("open_app");
+  });
+
+  it("should allow registering the same tool again", () => {
+    const [openApp] = withUniqueToolNames([openAppFactory]);
+    openApp?.(context);
+    expect(() => openApp?.(context)).not.toThrow();
+  });
+
+  it("should reject two tools with the same name", () => {
+    const [first, second] = withUniqueToolNames([
+      openAppFactory,
+      openAppFactory,
+    ]);
+    first?.(context);
+    expect(() => second?.(context)).toThrow("Duplicate tool name: open_app");
+  });
+});
+
+describe("getApiFactories", () => {
+  for (const profile of ["minimal", "full"] as const) {
+    it(`should register unique tool names for the ${profile} profile`, async () => {
+      const factories = await getApiFactories({ profile, prefix: "" });
+      expect(() => factories.map((factory) => factory(context))).not.toThrow();
+    });
+  }
+
+  it("should default to the minimal profile", () => {
+    expect(getToolProfile("")).toBe("minimal");
+  });
+
+  it("should include every minimal tool in the full profile", async () => {
+    const minimal = await describeTools({ profile: "minimal", prefix: "" });
+    const full = await describeTools({ profile: "full", prefix: "" });
+
+    expect(full.map((t) => t.name)).toEqual(
+      expect.arrayContaining(minimal.map((t) => t.name)),
+    );
+    expect(minimal.map((t) => t.name).sort()).toEqual([
+      "create_database",
+      "create_web_app",
+      "open_app",
+      "setup_app_schema",
+      "setup_testing",
+      "upload_env_to_vercel",
+      "view_skill",
+      "write_claude_md",
+    ]);
+  });
+});
@@ -1,16 +1,200 @@
+import { log } from "@tigerdata/mcp-boilerplate";
+import type { z } from "zod";
+import { isTraceEnabled, writeTrace } from "../../lib/trace.js";
+import { maxFieldBytes, truncateLargeFields } from "../../lib/truncate.js";
+import { context as defaultContext } from "../serverInfo.js";
+import { addContinuousAggregateFactory } from "./addContinuousAggregate.js";
+import { addCorsFactory } from "./addCors.js";
+import { addLoggingFactory } from "./addLogging.js";
+import { addProductionConfigFactory } from "./addProductionConfig.js";
+import { addSeoFactory } from "./addSeo.js";
+import { checkDatabaseFactory } from "./checkDatabase.js";
 import { createDatabaseFactory } from "./createDatabase.js";
 import { createWebAppFactory } from "./createWebApp.js";
+import { generateComponentFactory } from "./generateComponent.js";
+import { generatePageFactory } from "./generatePage.js";
+import { listRoutesFactory } from "./listRoutes.js";
+import { makeHypertableFactory } from "./makeHypertable.js";
 import { openAppFactory } from "./openApp.js";
+import { pushEnvFactory } from "./pushEnv.js";
+import { readTruncatedFactory } from "./readTruncated.js";
+import { runMigrationsFactory } from "./runMigrations.js";
 import { setupAppSchemaFactory } from "./setupAppSchema.js";
 import { setupTestingFactory } from "./setupTesting.js";
 import { uploadEnvToVercelFactory } from "./uploadEnvToVercel.js";
+import { validateProjectFactory } from "./validateProject.js";
 import { getViewSkillFactory } from "./viewSkill.js";
 import { writeClaudeMdFactory } from "./writeClaudeMd.js";
 
-export async function getApiFactories() {
+// Tool profiles control which tools get registered. Some clients cap the
+// number of tools they accept, so "minimal" keeps the original core set and
+// "full" adds the more granular tools on top.
+export const toolProfiles = ["minimal", "full"] as const;
+export type ToolProfile = (typeof toolProfiles)[number];
+
+const defaultToolProfile: ToolProfile = "minimal";
+
+export interface ToolOptions {
+  // Profile selects which tools are registered (default: minimal)
+  profile?: ToolProfile;
+  // Prefix is prepended to every tool name to avoid collisions (default: none)
+  prefix?: string;
+}
+
+/**
+ * Resolve the tool profile from an explicit value or OPERATOR_TOOL_PROFILE
+ */
+export function getToolProfile(
+  value = process.env.OPERATOR_TOOL_PROFILE,
+): ToolProfile {
+  if (!value) {
+    return defaultToolProfile;
+  }
+
+  const profile = toolProfiles.find((p) => p === value.toLowerCase());
+  if (!profile) {
+    log.warn(
+      `Unknown tool profile "${value}". Valid profiles: ${toolProfiles.join(", ")}. Using "${defaultToolProfile}".`,
+    );
+    return defaultToolProfile;
+  }
+
+  return profile;
+}
+
+/**
+ * Resolve the tool name prefix from an explicit value or OPERATOR_TOOL_PREFIX
+ */
+export function getToolPrefix(
+  value = process.env.OPERATOR_TOOL_PREFIX,
+): string {
+  const prefix = value?.trim() ?? "";
+  if (prefix && !/^[a-zA-Z0-9_-]+$/.test(prefix)) {
+    throw new Error(
+      `Invalid tool prefix "${prefix}". Use letters, numbers, underscores, or hyphens.`,
+    );
+  }
+  return prefix;
+}
+
+// biome-ignore lint/suspicious/noExplicitAny: tool factories have different input/output schemas
+type AnyToolFactory = (...args: any[]) => {
+  name: string;
+  config: {
+    title?: string;
+    description?: string;
+    inputSchema?: Record<string, z.ZodTypeAny>;
+  };
+  // biome-ignore lint/suspicious/noExplicitAny: see above
+  fn: (...args: any[]) => Promise<unknown>;
+};
+
+/**
+ * Wrap a tool factory so the tool it creates is registered as `${prefix}${name}`.
+ * Skills refer to tools by their bare names, so the description says so.
+ */
+function withToolPrefix<F extends AnyToolFactory>(
+  factory: F,
+  prefix: string,
+): F {
+  return ((...args: Parameters<F>) => {
+    const tool = factory(...args);
+    return {
+      ...tool,
+      name: `${prefix}${tool.name}`,
+      config: {
+        ...tool.config,
+        description: `${tool.config.description ?? ""}\n\n(Registered with the "${prefix}" name prefix. Skills refer to this tool as "${tool.name}".)`,
+      },
+    };
+  }) as F;
+}
+
+/**
+ * Wrap a tool factory so each call to the tool is appended to the trace file
+ * with its arguments, result or error, and duration (see OPERATOR_TRACE)
+ */
+function withToolTrace<F extends AnyToolFactory>(factory: F): F {
+  return ((...args: Parameters<F>) => {
+    const tool = factory(...args);
+    return {
+      ...tool,
+      fn: async (input: unknown, ...rest: unknown[]) => {
+        const start = performance.now();
+        const elapsed = () => Math.round(performance.now() - start);
+        try {
+          const result = await tool.fn(input, ...rest);
+          await writeTrace({
+            tool: tool.name,
+            args: input,
+            duration_ms: elapsed(),
+            result,
+          });
+          return result;
+        } catch (err) {
+          await writeTrace({
+            tool: tool.name,
+            args: input,
+            duration_ms: elapsed(),
+            error: (err as Error).message,
+          });
+          throw err;
+        }
+      },
+    };
+  }) as F;
+}
+
+/**
+ * Wrap a tool factory so oversized string fields in the tool's result are
+ * truncated before they reach the client. With readTool, the truncation note
+ * points at that tool for reading the rest.
+ */
+function withResponseLimit<F extends AnyToolFactory>(
+  factory: F,
+  readTool?: string,
+): F {
+  return ((...args: Parameters<F>) => {
+    const tool = factory(...args);
+    return {
+      ...tool,
+      fn: async (...input: unknown[]) =>
+        truncateLargeFields(await tool.fn(...input), maxFieldBytes, readTool),
+    };
+  }) as F;
+}
+
+/**
+ * Wrap tool factories so that two different factories registering the same
+ * name throw, naming the tool, instead of one silently shadowing the other.
+ * Names are checked as the server registers each tool, so no factory is
+ * called just for the check, and re-registering (e.g. one server per
+ * session) is fine.
+ */
+export function withUniqueToolNames<F extends AnyToolFactory>(
+  factories: readonly F[],
+): F[] {
+  const owners = new Map<string, number>();
+  return factories.map(
+    (factory, index) =>
+      ((...args: Parameters<F>) => {
+        const tool = factory(...args);
+        const owner = owners.get(tool.name);
+        if (owner !== undefined && owner !== index) {
+          throw new Error(`Duplicate tool name: ${tool.name}`);
+        }
+        owners.set(tool.name, index);
+        return tool;
+      }) as F,
+  );
+}
+
+export async function getApiFactories(options: ToolOptions = {}) {
+  const profile = options.profile ?? getToolProfile();
+  const prefix = options.prefix ?? getToolPrefix();
   const viewSkillFactory = await getViewSkillFactory();
 
-  return [
+  const minimalFactories = [
     createDatabaseFactory,
     createWebAppFactory,
     openAppFactory,
@@ -20,4 +204,71 @@ export async function getApiFactories() {
     viewSkillFactory,
     writeClaudeMdFactory,
   ] as const;
+
+  const factories = [
+    ...minimalFactories,
+    ...(profile === "full"
+      ? ([
+          addContinuousAggregateFactory,
+          addCorsFactory,
+          addLoggingFactory,
+          addProductionConfigFactory,
+          addSeoFactory,
+          checkDatabaseFactory,
+          generateComponentFactory,
+          generatePageFactory,
+          listRoutesFactory,
+          makeHypertableFactory,
+          pushEnvFactory,
+          readTruncatedFactory,
+          runMigrationsFactory,
+          validateProjectFactory,
+        ] as const)
+      : []),
+  ];
+
+  const prefixed = prefix
+    ? factories.map((factory) => withToolPrefix(factory, prefix))
+    : factories;
+  // Traces record the full result, so limit the response outside the trace
+  const traced = isTraceEnabled()
+    ? prefixed.map((factory) => withToolTrace(factory))
+    : prefixed;
+  // Only the full profile has read_truncated to page through cut-off fields
+  const readTool = profile === "full" ? `${prefix}read_truncated` : undefined;
+  return withUniqueToolNames(
+    traced.map((factory) => withResponseLimit(factory, readTool)),
+  );
+}
+
+export interface ToolSummary {
+  name: string;
+  title?: string | undefined;
+  description: string;
+  inputs: Array<{ name: string; required: boolean; description?: string }>;
+}
+
+/**
+ * Describe the tools the server would register with these options, without
+ * starting it
+ */
+export async function describeTools(
+  options: ToolOptions = {},
+): Promise<ToolSummary[]> {
+  const factories: readonly AnyToolFactory[] = await getApiFactories(options);
+  return factories.map((factory) => {
+    const { name, config } = factory(defaultContext);
+    return {
+      name,
+      title: config.title,
+      description: config.description ?? "",
+      inputs: Object.entries(config.inputSchema ?? {}).map(
+        ([input, schema]) => ({
+          name: input,
+          required: !schema.isOptional(),
+          ...(schema.description ? { description: schema.description } : {}),
+        }),
+      ),
+    };
+  });
 }
@@ -0,0 +1,91 @@
+import { mkdirSync, rmSync, writeFileSync } from "node:fs";
+import { tmpdir } from "node:os";
+import { dirname, join } from "node:path";
+import { afterEach, beforeEach, describe, expect, it } from "vitest";
+import { context } from "../serverInfo.js";
+import { listRoutesFactory } from "./listRoutes.js";
+
+const page = "export default function Page() {}\n";
+
+describe("list_routes", () => {
+  let appDir: string;
+
+  function write(path: string, content = page) {
+    const file = join(appDir, path);
+    mkdirSync(dirname(file), { recursive: true });
+    writeFileSync(file, content);
+  }
+
+  beforeEach(() => {
+    appDir = join(
+      tmpdir(),
+      `routes-test-${Date.now()}-${Math.random().toString(36).slice(2)}`,
+    );
+    mkdirSync(appDir, { recursive: true });
+  });
+
+  afterEach(() => {
+    rmSync(appDir, { recursive: true, force: true });
+  });
+
+  it("should leave route groups out of App Router URLs", async () => {
+    write("src/app/page.tsx");
+    write("src/app/(auth)/login/page.tsx");
+    write("src/app/_components/button/page.tsx");
+
+    const result = await listRoutesFactory(context).fn({
+      application_directory: appDir,
+    });
+
+    expect(result.router).toBe("app");
+    expect(result.routes?.map((r) => r.url)).toEqual(["/", "/login"]);
+  });
+
+  it("should keep dynamic segments and list API methods", async () => {
+    write("src/app/posts/[id]/page.tsx");
+    write(
+      "src/app/api/posts/[id]/route.ts",
+      "export async function GET() {}\nexport async function DELETE() {}\n",
+    );
+
+    const result = await listRoutesFactory(context).fn({
+      application_directory: appDir,
+    });
+
+    expect(result.routes).toEqual([
+      {
+        url: "/api/posts/[id]",
+        kind: "api",
+        file: join("src", "app", "api", "posts", "[id]", "route.ts"),
+        methods: ["GET", "DELETE"],
+        protected: false,
+      },
+      {
+        url: "/posts/[id]",
+        kind: "page",
+        file: join("src", "app", "posts", "[id]", "page.tsx"),
+        protected: false,
+      },
+    ]);
+  });
+
+  it("should list Pages Router files but not tests or special files", async () => {
+    write("pages/index.tsx");
+    write("pages/_app.tsx");
+    write("pages/blog/[slug].tsx");
+    write("pages/blog/[slug].test.tsx");
+    write("pages/about.spec.ts");
+    write("pages/api/health.ts");
+
+    const result = await listRoutesFactory(context).fn({
+      application_directory: appDir,
+    });
+
+    expect(result.router).toBe("pages");
+    expect(result.routes?.map((r) => [r.url, r.kind])).toEqual([
+      ["/", "page"],
+      ["/api/health", "api"],
+      ["/blog/[slug]", "page"],
+    ]);
+  });
+});
@@ -0,0 +1,221 @@
+import { existsSync } from "node:fs";
+import { readdir, readFile } from "node:fs/promises";
+import { join, relative, resolve } from "node:path";
+import type { ApiFactory } from "@tigerdata/mcp-boilerplate";
+import { z } from "zod";
+import type { ServerContext } from "../../types.js";
+
+const inputSchema = {
+  application_directory: z
+    .string()
+    .default(".")
+    .describe("Path to the application directory"),
+} as const;
+
+const routeSchema = z.object({
+  url: z.string().describe("URL path, with dynamic segments like [id]"),
+  kind: z.enum(["page", "api"]).describe("Page or API route handler"),
+  file: z.string().describe("Source file, relative to the app directory"),
+  methods: z
+    .array(z.string())
+    .optional()
+    .describe("HTTP methods exported by an API route"),
+  protected: z
+    .boolean()
+    .describe("Whether the file checks for an auth session"),
+});
+
+const outputSchema = {
+  success: z.boolean().describe("Whether routes were listed"),
+  message: z.string().describe("Status message"),
+  routes: z.array(routeSchema).optional().describe("Routes found in the app"),
+  router: z
+    .enum(["app", "pages", "both"])
+    .optional()
+    .describe("Which Next.js router(s) the app uses"),
+} as const;
+
+export type Route = z.infer<typeof routeSchema>;
+
+type OutputSchema = {
+  success: boolean;
+  message: string;
+  routes?: Route[];
+  router?: "app" | "pages" | "both";
+};
+
+const httpMethods = [
+  "GET",
+  "POST",
+  "PUT",
+  "PATCH",
+  "DELETE",
+  "HEAD",
+  "OPTIONS",
+];
+const pageFiles = new Set(["page.tsx", "page.ts", "page.jsx", "page.js"]);
+const routeFiles = new Set(["route.ts", "route.js"]);
+const pagesRouterExtensions = /\.(tsx|ts|jsx|js)$/;
+// Tests colocated with pages aren't routes
+const testFiles = /\.(test|spec)\.(tsx|ts|jsx|js)$/;
+// Pages Router files that aren't routes
+const pagesRouterSpecial = new Set([
+  "_app",
+  "_document",
+  "_error",
+  "middleware",
+]);
+
+function segmentsToUrl(segments: string[]): string {
+  // Route groups like (auth) and parallel routes like @modal aren't in the URL
+  const visible = segments.filter(
+    (s) => !/^\(.*\)$/.test(s) && !s.startsWith("@"),
+  );
+  return `/${visible.join("/")}`;
+}
+
+function findExportedMethods(source: string): string[] {
+  return httpMethods.filter((method) =>
+    new RegExp(
+      // export function GET / export const GET / export { handler as GET } / export const { GET }
+      `export\\s+(async\\s+)?(function|const|let)\\s+${method}\\b|export\\s*(const\\s*)?\\{[^}]*\\b${method}\\b[^}]*\\}`,
+    ).test(source),
+  );
+}
+
+function isProtected(source: string): boolean {
+  return /getSession|auth\.api\.|protectedProcedure/.test(source);
+}
+
+/**
+ * Recursively collect App Router pages and route handlers under dir
+ */
+export async function collectRoutes(
+  appDir: string,
+  dir: string,
+  segments: string[],
+  routes: Route[],
+): Promise<void> {
+  const entries = await readdir(dir, { withFileTypes: true });
+
+  for (const entry of entries) {
+    const entryPath = join(dir, entry.name);
+
+    if (entry.isDirectory()) {
+      // Private folders (_components) are not routable
+      if (entry.name.startsWith("_")) continue;
+      await collectRoutes(appDir, entryPath, [...segments, entry.name], routes);
+      continue;
+    }
+
+    const isPage = pageFiles.has(entry.name);
+    const isRoute = routeFiles.has(entry.name);
+    if (!isPage && !isRoute) continue;
+
+    const source = await readFile(entryPath, "utf-8");
+    routes.push({
+      url: segmentsToUrl(segments),
+      kind: isPage ? "page" : "api",
+      file: relative(appDir, entryPath),
+      ...(isRoute ? { methods: findExportedMethods(source) } : {}),
+      protected: isProtected(source),
+    });
+  }
+}
+
+async function collectPagesRoutes(
+  appDir: string,
+  dir: string,
+  segments: string[],
+  routes: Route[],
+): Promise<void> {
+  const entries = await readdir(dir, { withFileTypes: true });
+
+  for (const entry of entries) {
+    const entryPath = join(dir, entry.name);
+
+    if (entry.isDirectory()) {
+      await collectPagesRoutes(
+        appDir,
+        entryPath,
+        [...segments, entry.name],
+        routes,
+      );
+      continue;
+    }
+
+    if (!pagesRouterExtensions.test(entry.name)) continue;
+    if (testFiles.test(entry.name)) continue;
+    const name = entry.name.replace(pagesRouterExtensions, "");
+    if (pagesRouterSpecial.has(name)) continue;
+
+    const urlSegments = name === "index" ? segments : [...segments, name];
+    const source = await readFile(entryPath, "utf-8");
+    routes.push({
+      url: segmentsToUrl(urlSegments),
+      kind: segments[0] === "api" ? "api" : "page",
+      file: relative(appDir, entryPath),
+      protected: isProtected(source),
+    });
+  }
+}
+
+export const listRoutesFactory: ApiFactory<
+  ServerContext,
+  typeof inputSchema,
+  typeof outputSchema
+> = () => {
+  return {
+    name: "list_routes",
+    config: {
+      title: "List Routes",
+      description:
+        "🗺️ List a Next.js app's pages and API routes (App Router or Pages Router) with their URL paths, HTTP methods, and whether they check for an auth session. Use before adding features or writing tests.",
+      inputSchema,
+      outputSchema,
+    },
+    fn: async ({ application_directory }): Promise<OutputSchema> => {
+      const appDir = resolve(process.cwd(), application_directory);
+      const routesDir = [join(appDir, "src", "app"), join(appDir, "app")].find(
+        (d) => existsSync(d),
+      );
+      const pagesDir = [
+        join(appDir, "src", "pages"),
+        join(appDir, "pages"),
+      ].find((d) => existsSync(d));
+
+      if (!routesDir && !pagesDir) {
+        return {
+          success: false,
+          message: `No src/app, app, src/pages, or pages directory found in ${appDir}`,
+        };
+      }
+
+      const routes: Route[] = [];
+      try {
+        if (routesDir) {
+          await collectRoutes(appDir, routesDir, [], routes);
+        }
+        if (pagesDir) {
+          await collectPagesRoutes(appDir, pagesDir, [], routes);
+        }
+      } catch (err) {
+        const error = err as Error;
+        return {
+          success: false,
+          message: `Failed to list routes: ${error.message}`,
+        };
+      }
+
+      routes.sort((a, b) => a.url.localeCompare(b.url));
+      const pages = routes.filter((r) => r.kind === "page").length;
+
+      return {
+        success: true,
+        message: `Found ${pages} page(s) and ${routes.length - pages} API route(s)`,
+        routes,
+        router: routesDir && pagesDir ? "both" : routesDir ? "app" : "pages",
+      };
+    },
+  };
+};
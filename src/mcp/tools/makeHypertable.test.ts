@@ -0,0 +1,32 @@
+import { describe, expect, it } from "vitest";
+import { chunkInterval } from "./makeHypertable.js";
+
+describe("chunkInterval", () => {
+  it("should use an interval for timestamp columns", () => {
+    expect(
+      chunkInterval("timestamp with time zone", "7 days", undefined),
+    ).toEqual({ interval: "7 days" });
+  });
+
+  it("should use chunk_size for integer columns", () => {
+    expect(chunkInterval("bigint", "7 days", 86400)).toEqual({ size: 86400 });
+  });
+
+  it("should require chunk_size for integer columns", () => {
+    expect(chunkInterval("integer", "7 days", undefined)).toEqual({
+      error: expect.stringContaining("need chunk_size"),
+    });
+  });
+
+  it("should reject chunk_size for timestamp columns", () => {
+    expect(chunkInterval("date", "7 days", 100)).toEqual({
+      error: expect.stringContaining("only applies to integer"),
+    });
+  });
+
+  it("should reject other column types", () => {
+    expect(chunkInterval("text", "7 days", undefined)).toEqual({
+      error: expect.stringContaining("can't be a time column"),
+    });
+  });
+});
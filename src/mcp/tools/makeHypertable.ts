@@ -0,0 +1,244 @@
+import type { ApiFactory } from "@tigerdata/mcp-boilerplate";
+import postgres from "postgres";
+import { z } from "zod";
+import { databaseUrlInputs, resolveDatabaseUrl } from "../../lib/database.js";
+import type { ServerContext } from "../../types.js";
+
+const identifier = /^[a-z_][a-z0-9_]*$/;
+
+const inputSchema = {
+  ...databaseUrlInputs,
+  table: z
+    .string()
+    .regex(
+      /^([a-z_][a-z0-9_]*\.)?[a-z_][a-z0-9_]*$/,
+      "Table must be a lowercase identifier, optionally schema-qualified",
+    )
+    .describe(
+      "Table to convert, e.g. events or my_app.events (default: found on the search_path)",
+    ),
+  time_column: z
+    .string()
+    .regex(identifier, "Column must be a lowercase identifier")
+    .describe(
+      "Timestamp, date, or integer column to partition by, e.g. created_at",
+    ),
+  chunk_time_interval: z
+    .string()
+    .regex(
+      /^\d+\s+(minute|hour|day|week|month)s?$/,
+      "Interval looks like '7 days' or '1 hour'",
+    )
+    .default("7 days")
+    .describe(
+      "Time span covered by each chunk, for timestamp and date columns",
+    ),
+  chunk_size: z
+    .number()
+    .int()
+    .positive()
+    .optional()
+    .describe(
+      "Values of the time column covered by each chunk. Required for integer time columns (e.g. 86400 for one day of epoch seconds)",
+    ),
+  migrate_data: z
+    .boolean()
+    .default(false)
+    .describe(
+      "Move existing rows into chunks. Required if the table has data; locks the table while it runs",
+    ),
+} as const;
+
+const outputSchema = {
+  success: z.boolean().describe("Whether the table is now a hypertable"),
+  message: z.string().describe("Status message"),
+  status: z
+    .enum(["created", "already_hypertable", "skipped"])
+    .optional()
+    .describe(
+      "created, already_hypertable, or skipped because TimescaleDB is not installed",
+    ),
+  table: z.string().optional().describe("Schema-qualified table name"),
+} as const;
+
+type OutputSchema = {
+  success: boolean;
+  message: string;
+  status?: "created" | "already_hypertable" | "skipped";
+  table?: string;
+};
+
+type ColumnRow = {
+  table_schema: string;
+  data_type: string;
+};
+
+const intervalTypes = new Set([
+  "timestamp with time zone",
+  "timestamp without time zone",
+  "date",
+]);
+const integerTypes = new Set(["smallint", "integer", "bigint"]);
+
+export type ChunkInterval =
+  | { interval: string }
+  | { size: number }
+  | { error: string };
+
+/**
+ * Pick create_hypertable's chunk_time_interval for a time column type:
+ * an interval for timestamps and dates, a plain number for integers
+ */
+export function chunkInterval(
+  dataType: string,
+  chunkTimeInterval: string,
+  chunkSize: number | undefined,
+): ChunkInterval {
+  if (integerTypes.has(dataType)) {
+    return chunkSize === undefined
+      ? {
+          error: `Integer time columns (${dataType}) need chunk_size, the number of time values per chunk`,
+        }
+      : { size: chunkSize };
+  }
+  if (intervalTypes.has(dataType)) {
+    return chunkSize === undefined
+      ? { interval: chunkTimeInterval }
+      : {
+          error: `chunk_size only applies to integer time columns; use chunk_time_interval for ${dataType}`,
+        };
+  }
+  return {
+    error: `Column type ${dataType} can't be a time column; hypertables need a timestamp, date, or integer column`,
+  };
+}
+
+export const makeHypertableFactory: ApiFactory<
+  ServerContext,
+  typeof inputSchema,
+  typeof outputSchema
+> = () => {
+  return {
+    name: "make_hypertable",
+    config: {
+      title: "Make Hypertable",
+      description:
+        "⏱️ Convert an existing table into a TimescaleDB hypertable partitioned by a time column, for faster time-series queries and retention. Skips gracefully if TimescaleDB isn't installed.",
+      inputSchema,
+      outputSchema,
+    },
+    fn: async (input): Promise<OutputSchema> => {
+      const resolved = await resolveDatabaseUrl(input);
+      if ("error" in resolved) {
+        return { success: false, message: resolved.error };
+      }
+
+      const [schemaName, tableName] = input.table.includes(".")
+        ? input.table.split(".")
+        : [undefined, input.table];
+      const sql = postgres(resolved.databaseUrl, {
+        max: 1,
+        connect_timeout: 10,
+        onnotice: () => {},
+      });
+
+      try {
+        const columns = await sql<ColumnRow[]>`
+          SELECT table_schema, data_type
+          FROM information_schema.columns
+          WHERE table_name = ${tableName ?? ""}
+            AND column_name = ${input.time_column}
+            AND ${
+              schemaName
+                ? sql`table_schema = ${schemaName}`
+                : sql`table_schema = ANY (current_schemas(false))`
+            }
+          ORDER BY array_position(current_schemas(false), table_schema::name)
+        `;
+        const column = columns[0];
+        if (!column) {
+          return {
+            success: false,
+            message: `Table ${input.table} with column ${input.time_column} was not found`,
+          };
+        }
+
+        const qualified = `${column.table_schema}.${tableName}`;
+        const chunk = chunkInterval(
+          column.data_type,
+          input.chunk_time_interval,
+          input.chunk_size,
+        );
+        if ("error" in chunk) {
+          return {
+            success: false,
+            message: `${qualified}.${input.time_column}: ${chunk.error}`,
+            table: qualified,
+          };
+        }
+
+        const extension = await sql`
+          SELECT 1 FROM pg_extension WHERE extname = 'timescaledb'
+        `;
+        if (extension.length === 0) {
+          return {
+            success: true,
+            message: `TimescaleDB is not installed, so ${qualified} was left as a regular table`,
+            status: "skipped",
+            table: qualified,
+          };
+        }
+
+        const existing = await sql`
+          SELECT 1 FROM timescaledb_information.hypertables
+          WHERE hypertable_schema = ${column.table_schema}
+            AND hypertable_name = ${tableName ?? ""}
+        `;
+        if (existing.length > 0) {
+          return {
+            success: true,
+            message: `${qualified} is already a hypertable`,
+            status: "already_hypertable",
+            table: qualified,
+          };
+        }
+
+        // Integer columns take a plain number; an interval cast fails there
+        const chunkArg =
+          "size" in chunk
+            ? sql`${chunk.size}::bigint`
+            : sql`${chunk.interval}::interval`;
+        await sql`
+          SELECT create_hypertable(
+            ${qualified}::regclass,
+            ${input.time_column}::name,
+            chunk_time_interval => ${chunkArg},
+            migrate_data => ${input.migrate_data}
+          )
+        `;
+
+        const chunkDescription =
+          "size" in chunk ? `${chunk.size}-value` : chunk.interval;
+        return {
+          success: true,
+          message: `Converted ${qualified} to a hypertable on ${input.time_column} with ${chunkDescription} chunks`,
+          status: "created",
+          table: qualified,
+        };
+      } catch (err) {
+        const error = err as Error;
+        const hint = /unique index|primary key/i.test(error.message)
+          ? ` Unique constraints and primary keys must include ${input.time_column}.`
+          : /not empty/i.test(error.message)
+            ? " Set migrate_data to true to move existing rows."
+            : "";
+        return {
+          success: false,
+          message: `Failed to create hypertable: ${error.message}.${hint}`,
+        };
+      } finally {
+        await sql.end();
+      }
+    },
+  };
+};
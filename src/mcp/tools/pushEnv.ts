@@ -0,0 +1,227 @@
+import { existsSync } from "node:fs";
+import { join, resolve } from "node:path";
+import type { ApiFactory } from "@tigerdata/mcp-boilerplate";
+import { z } from "zod";
+import { readEnvFile } from "../../lib/env.js";
+import {
+  type DeployPlatform,
+  deployPlatforms,
+  detectPlatform,
+  pushFlySecrets,
+  pushRailwayVariable,
+  vercelEnvironments,
+} from "../../lib/platformEnv.js";
+import type { ServerContext } from "../../types.js";
+import { type FailedVar, uploadVercelVars } from "./uploadEnvToVercel.js";
+
+const inputSchema = {
+  application_directory: z
+    .string()
+    .default(".")
+    .describe("Path to the application directory"),
+  platform: z
+    .enum(deployPlatforms)
+    .optional()
+    .describe(
+      "Deployment platform. Detected from .vercel/vercel.json, fly.toml, or railway.json/railway.toml when omitted",
+    ),
+  env_file: z
+    .string()
+    .optional()
+    .describe(
+      "Env file relative to application_directory (default: .env.local if it exists, otherwise .env)",
+    ),
+  vercel_environments: z
+    .array(z.enum(vercelEnvironments))
+    .default(["production", "preview"])
+    .describe("Vercel environments to set (ignored for other platforms)"),
+} as const;
+
+const outputSchema = {
+  success: z.boolean().describe("Whether all variables were set"),
+  message: z.string().describe("Status message"),
+  platform: z.enum(deployPlatforms).optional().describe("Target platform"),
+  env_file: z.string().optional().describe("Env file the variables came from"),
+  set: z
+    .array(z.string())
+    .optional()
+    .describe("Names of variables that were set (values are never returned)"),
+  failed: z
+    .array(z.object({ name: z.string(), error: z.string() }))
+    .optional()
+    .describe("Variables that failed, with secrets redacted from the error"),
+  skipped_empty: z
+    .array(z.string())
+    .optional()
+    .describe("Variables skipped because they had empty values"),
+  skipped_public: z
+    .array(z.string())
+    .optional()
+    .describe(
+      "NEXT_PUBLIC_* variables skipped because they are inlined at build time and must be passed as build args on this platform",
+    ),
+} as const;
+
+type OutputSchema = {
+  success: boolean;
+  message: string;
+  platform?: DeployPlatform;
+  env_file?: string;
+  set?: string[];
+  failed?: FailedVar[];
+  skipped_empty?: string[];
+  skipped_public?: string[];
+};
+
+const platformNames: Record<DeployPlatform, string> = {
+  vercel: "Vercel",
+  fly: "Fly.io",
+  railway: "Railway",
+};
+
+function defaultEnvFile(appDir: string): string {
+  return existsSync(join(appDir, ".env.local")) ? ".env.local" : ".env";
+}
+
+export const pushEnvFactory: ApiFactory<
+  ServerContext,
+  typeof inputSchema,
+  typeof outputSchema
+> = () => {
+  return {
+    name: "push_env",
+    config: {
+      title: "Push Env to Deployment",
+      description:
+        "🔐 Push environment variables from the app's env file to its deployment platform (Vercel, Fly.io, or Railway) using the platform CLI. Returns the names of variables that were set; values are never included in the output.",
+      inputSchema,
+      outputSchema,
+    },
+    fn: async ({
+      application_directory,
+      platform: requestedPlatform,
+      env_file,
+      vercel_environments,
+    }): Promise<OutputSchema> => {
+      const appDir = resolve(process.cwd(), application_directory);
+
+      const platform = requestedPlatform ?? detectPlatform(appDir);
+      if (!platform) {
+        return {
+          success: false,
+          message: `Could not detect a deployment platform in ${appDir}. Link the app first (vercel link, fly launch, or railway link) or pass platform explicitly.`,
+        };
+      }
+
+      const envFile = env_file ?? defaultEnvFile(appDir);
+      const envPath = resolve(appDir, envFile);
+      if (!existsSync(envPath)) {
+        return {
+          success: false,
+          message: `Env file not found at: ${envPath}`,
+          platform,
+        };
+      }
+
+      let parsed: Record<string, string>;
+      try {
+        parsed = await readEnvFile(envPath);
+      } catch (err) {
+        const error = err as Error;
+        return {
+          success: false,
+          message: `Failed to read ${envFile}: ${error.message}`,
+          platform,
+        };
+      }
+
+      const vars: Record<string, string> = {};
+      const skippedEmpty: string[] = [];
+      const skippedPublic: string[] = [];
+      for (const [name, value] of Object.entries(parsed)) {
+        if (!value) {
+          skippedEmpty.push(name);
+        } else if (platform === "fly" && name.startsWith("NEXT_PUBLIC_")) {
+          // Fly secrets are runtime-only, but Next.js inlines these at build
+          skippedPublic.push(name);
+        } else {
+          vars[name] = value;
+        }
+      }
+
+      const skipped = {
+        ...(skippedEmpty.length > 0 ? { skipped_empty: skippedEmpty } : {}),
+        ...(skippedPublic.length > 0 ? { skipped_public: skippedPublic } : {}),
+      };
+
+      const names = Object.keys(vars);
+      if (names.length === 0) {
+        return {
+          success: false,
+          message: `No environment variables with values found in ${envFile}`,
+          platform,
+          env_file: envFile,
+          ...skipped,
+        };
+      }
+
+      const set: string[] = [];
+      const failed: FailedVar[] = [];
+
+      if (platform === "vercel") {
+        const result = await uploadVercelVars(appDir, vars, vercel_environments);
+        set.push(...result.uploaded);
+        failed.push(...result.failed);
+      } else if (platform === "railway") {
+        // Values go over stdin one at a time; only the last one redeploys
+        const entries = Object.entries(vars);
+        for (const [i, [name, value]] of entries.entries()) {
+          try {
+            await pushRailwayVariable(appDir, name, value, {
+              deploy: i === entries.length - 1,
+            });
+            set.push(name);
+          } catch (err) {
+            failed.push({ name, error: (err as Error).message });
+          }
+        }
+      } else {
+        // Fly sets everything in one release that succeeds or fails
+        try {
+          await pushFlySecrets(appDir, vars);
+          set.push(...names);
+        } catch (err) {
+          const message = (err as Error).message;
+          failed.push(...names.map((name) => ({ name, error: message })));
+        }
+      }
+
+      const target = platformNames[platform];
+      const result = { platform, env_file: envFile, ...skipped };
+
+      if (failed.length === 0) {
+        return {
+          success: true,
+          message: `Set ${set.length} environment variables on ${target}`,
+          set,
+          ...result,
+        };
+      }
+      if (set.length === 0) {
+        return {
+          success: false,
+          message: `Failed to set any environment variables on ${target}`,
+          failed,
+          ...result,
+        };
+      }
+      return {
+        success: false,
+        message: `Partially completed on ${target}: ${set.length} set, ${failed.length} failed`,
+        set,
+        failed,
+        ...result,
+      };
+    },
+  };
+};
@@ -0,0 +1,77 @@
+import type { ApiFactory } from "@tigerdata/mcp-boilerplate";
+import { z } from "zod";
+import { maxFieldBytes, readTruncatedField } from "../../lib/truncate.js";
+import type { ServerContext } from "../../types.js";
+
+const inputSchema = {
+  id: z.string().describe("id from the [truncated ...] note, e.g. t3"),
+  offset: z
+    .number()
+    .int()
+    .min(0)
+    .default(0)
+    .describe("Byte offset to read from, from the note or next_offset"),
+} as const;
+
+const outputSchema = {
+  success: z.boolean().describe("Whether the truncated field was found"),
+  message: z.string().describe("Status message"),
+  text: z
+    .string()
+    .optional()
+    .describe(`Up to ${maxFieldBytes} bytes of the field from offset`),
+  total_bytes: z.number().optional().describe("Full size of the field"),
+  next_offset: z
+    .number()
+    .optional()
+    .describe("Offset of the next page; absent when this is the end"),
+} as const;
+
+type OutputSchema = {
+  success: boolean;
+  message: string;
+  text?: string;
+  total_bytes?: number;
+  next_offset?: number;
+};
+
+export const readTruncatedFactory: ApiFactory<
+  ServerContext,
+  typeof inputSchema,
+  typeof outputSchema
+> = () => {
+  return {
+    name: "read_truncated",
+    config: {
+      title: "Read Truncated Result",
+      description:
+        "📄 Read the rest of a tool result field that was cut off with a [truncated N bytes] note, one page at a time. Pass the id and offset from the note, then next_offset until it is absent.",
+      inputSchema,
+      outputSchema,
+    },
+    fn: async ({ id, offset }): Promise<OutputSchema> => {
+      const page = readTruncatedField(id, offset);
+      if (!page) {
+        return {
+          success: false,
+          message: `No truncated result with id ${id}. Only recent results are kept; run the tool again.`,
+        };
+      }
+      if (offset > page.total_bytes) {
+        return {
+          success: false,
+          message: `Offset ${offset} is past the end (${page.total_bytes} bytes)`,
+        };
+      }
+
+      return {
+        success: true,
+        message:
+          page.next_offset !== undefined
+            ? `Read bytes ${offset}-${page.next_offset} of ${page.total_bytes}`
+            : `Read bytes ${offset}-${page.total_bytes} of ${page.total_bytes} (end)`,
+        ...page,
+      };
+    },
+  };
+};
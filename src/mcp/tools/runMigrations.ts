@@ -0,0 +1,168 @@
+import { existsSync } from "node:fs";
+import { readdir, readFile } from "node:fs/promises";
+import { join, resolve } from "node:path";
+import type { ApiFactory } from "@tigerdata/mcp-boilerplate";
+import postgres from "postgres";
+import { z } from "zod";
+import { databaseUrlInputs, resolveDatabaseUrl } from "../../lib/database.js";
+import type { ServerContext } from "../../types.js";
+
+const inputSchema = {
+  ...databaseUrlInputs,
+  migrations_dir: z
+    .string()
+    .default("migrations")
+    .describe(
+      "Directory of .sql migration files, relative to application_directory. Files are applied in filename order.",
+    ),
+  dry_run: z
+    .boolean()
+    .default(false)
+    .describe(
+      "List pending migrations without applying them or changing the database",
+    ),
+} as const;
+
+const outputSchema = {
+  success: z.boolean().describe("Whether all pending migrations were applied"),
+  message: z.string().describe("Status message"),
+  applied: z
+    .array(z.string())
+    .optional()
+    .describe("Migrations applied by this run"),
+  pending: z
+    .array(z.string())
+    .optional()
+    .describe("Migrations not yet applied (dry run or after a failure)"),
+} as const;
+
+type OutputSchema = {
+  success: boolean;
+  message: string;
+  applied?: string[];
+  pending?: string[];
+};
+
+export const runMigrationsFactory: ApiFactory<
+  ServerContext,
+  typeof inputSchema,
+  typeof outputSchema
+> = () => {
+  return {
+    name: "run_migrations",
+    config: {
+      title: "Run Migrations",
+      description:
+        "🔁 Apply pending SQL migrations to the app's database. Reads migrations/*.sql, applies those not yet recorded in schema_migrations in filename order (each in its own transaction), and records them. For Drizzle schema changes use `npm run db:push` instead.",
+      inputSchema,
+      outputSchema,
+    },
+    fn: async (input): Promise<OutputSchema> => {
+      const { application_directory, migrations_dir, dry_run } = input;
+      const appDir = resolve(process.cwd(), application_directory);
+      const migrationsPath = join(appDir, migrations_dir);
+
+      if (!existsSync(migrationsPath)) {
+        return {
+          success: false,
+          message: `Migrations directory not found: ${migrationsPath}`,
+        };
+      }
+
+      const files = (await readdir(migrationsPath))
+        .filter((f) => f.endsWith(".sql"))
+        .sort();
+
+      const resolved = await resolveDatabaseUrl(input);
+      if ("error" in resolved) {
+        return { success: false, message: resolved.error };
+      }
+
+      const sql = postgres(resolved.databaseUrl, {
+        max: 1,
+        onnotice: () => {},
+      });
+      const applied: string[] = [];
+
+      try {
+        // A dry run only reads: a missing table means nothing is applied yet
+        let rows: { version: string }[] = [];
+        if (dry_run) {
+          const [table] = await sql<{ exists: boolean }[]>`
+            SELECT to_regclass('schema_migrations') IS NOT NULL AS exists
+          `;
+          if (table?.exists) {
+            rows = await sql<{ version: string }[]>`
+              SELECT version FROM schema_migrations
+            `;
+          }
+        } else {
+          await sql`
+            CREATE TABLE IF NOT EXISTS schema_migrations (
+              version TEXT PRIMARY KEY,
+              applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+            )
+          `;
+          rows = await sql<{ version: string }[]>`
+            SELECT version FROM schema_migrations
+          `;
+        }
+        const done = new Set(rows.map((r) => r.version));
+        const pending = files.filter((f) => !done.has(f));
+
+        if (pending.length === 0) {
+          return {
+            success: true,
+            message: "No pending migrations",
+            applied,
+            pending,
+          };
+        }
+
+        if (dry_run) {
+          return {
+            success: true,
+            message: `${pending.length} pending migration(s) would be applied`,
+            applied,
+            pending,
+          };
+        }
+
+        for (const file of pending) {
+          const content = await readFile(join(migrationsPath, file), "utf-8");
+          try {
+            await sql.begin(async (tx) => {
+              await tx.unsafe(content);
+              await tx`INSERT INTO schema_migrations (version) VALUES (${file})`;
+            });
+          } catch (err) {
+            const error = err as Error;
+            return {
+              success: false,
+              message: `Migration ${file} failed: ${error.message}`,
+              applied,
+              pending: pending.slice(applied.length),
+            };
+          }
+          applied.push(file);
+        }
+
+        return {
+          success: true,
+          message: `Applied ${applied.length} migration(s)`,
+          applied,
+          pending: [],
+        };
+      } catch (err) {
+        const error = err as Error;
+        return {
+          success: false,
+          message: `Failed to run migrations: ${error.message}`,
+          applied,
+        };
+      } finally {
+        await sql.end();
+      }
+    },
+  };
+};
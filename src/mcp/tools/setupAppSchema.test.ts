@@ -0,0 +1,30 @@
+import { describe, expect, it } from "vitest";
+import { context } from "../serverInfo.js";
+import { setupAppSchemaFactory } from "./setupAppSchema.js";
+
+describe("setup_app_schema", () => {
+  it("should refuse an env file outside the app", async () => {
+    const result = await setupAppSchemaFactory(context).fn({
+      application_directory: "app",
+      service_id: "svc123",
+      app_name: "my_app",
+      env_file: "../outside.env",
+    });
+
+    expect(result.success).toBe(false);
+    expect(result.message).toContain("outside the application directory");
+  });
+
+  it("should refuse an SQL file outside the app", async () => {
+    const result = await setupAppSchemaFactory(context).fn({
+      application_directory: "app",
+      service_id: "svc123",
+      app_name: "my_app",
+      env_file: ".env",
+      sql_file: "../schema.sql",
+    });
+
+    expect(result.success).toBe(false);
+    expect(result.message).toContain("outside the application directory");
+  });
+});
@@ -1,16 +1,13 @@
-import { exec } from "node:child_process";
-import { existsSync } from "node:fs";
-import { readFile, writeFile } from "node:fs/promises";
-import { join, resolve } from "node:path";
-import { promisify } from "node:util";
+import { mkdir, writeFile } from "node:fs/promises";
+import { dirname, isAbsolute, relative, resolve } from "node:path";
 import type { ApiFactory } from "@tigerdata/mcp-boilerplate";
-import * as dotenv from "dotenv";
 import postgres from "postgres";
 import { z } from "zod";
+import { withCredentials } from "../../lib/connectionString.js";
+import { readEnvFile, updateEnvFile } from "../../lib/env.js";
+import { getConnectionString } from "../../lib/tiger.js";
 import type { ServerContext } from "../../types.js";
 
-const execAsync = promisify(exec);
-
 const inputSchema = {
   application_directory: z
     .string()
@@ -25,6 +22,18 @@ const inputSchema = {
     .describe(
       "Application name (used for schema and user name, must be lowercase with underscores)",
     ),
+  env_file: z
+    .string()
+    .default(".env")
+    .describe(
+      "Env file to write DATABASE_URL to, relative to application_directory (default: .env, the T3 convention)",
+    ),
+  sql_file: z
+    .string()
+    .optional()
+    .describe(
+      "Also write the applied SQL (password redacted) to this file relative to application_directory, e.g. db/schema.sql, for review or version control",
+    ),
 } as const;
 
 const outputSchema = {
@@ -32,6 +41,18 @@ const outputSchema = {
   message: z.string().describe("Status message"),
   schema_name: z.string().optional().describe("Name of the created schema"),
   user_name: z.string().optional().describe("Name of the created user"),
+  env_file: z
+    .string()
+    .optional()
+    .describe("Env file DATABASE_URL was written to"),
+  sql: z
+    .string()
+    .optional()
+    .describe("SQL statements that were applied, with the password redacted"),
+  sql_file: z
+    .string()
+    .optional()
+    .describe("File the applied SQL was written to"),
 } as const;
 
 type OutputSchema = {
@@ -39,6 +60,9 @@ type OutputSchema = {
   message: string;
   schema_name?: string | undefined;
   user_name?: string | undefined;
+  env_file?: string | undefined;
+  sql?: string | undefined;
+  sql_file?: string | undefined;
 };
 
 function generatePassword(length = 24): string {
@@ -51,28 +75,17 @@ function generatePassword(length = 24): string {
   return password;
 }
 
-function buildConnectionString(
-  originalUrl: string,
-  user: string,
-  password: string,
-): string {
-  const parsed = new URL(originalUrl);
-  parsed.username = user;
-  parsed.password = encodeURIComponent(password);
-  return parsed.toString();
-}
-
 export const setupAppSchemaFactory: ApiFactory<
   ServerContext,
   typeof inputSchema,
   typeof outputSchema
-> = () => {
+> = ({ tiger }) => {
   return {
     name: "setup_app_schema",
     config: {
       title: "Setup App Schema",
       description:
-        "🗄️ Set up database schema and user for the application. Creates a PostgreSQL schema and user named after the app, with appropriate permissions, and writes DATABASE_URL to .env.",
+        "🗄️ Set up database schema and user for the application. Creates a PostgreSQL schema and user named after the app, with appropriate permissions, and writes DATABASE_URL to .env (or env_file).",
       inputSchema,
       outputSchema,
     },
@@ -80,53 +93,64 @@ export const setupAppSchemaFactory: ApiFactory<
       application_directory,
       service_id,
       app_name,
+      env_file,
+      sql_file,
     }): Promise<OutputSchema> => {
       const appDir = resolve(process.cwd(), application_directory);
-      const envPath = join(appDir, ".env");
+      const envPath = resolve(appDir, env_file);
+      const envRelPath = relative(appDir, envPath);
+      if (envRelPath.startsWith("..") || isAbsolute(envRelPath)) {
+        return {
+          success: false,
+          message: `Env file ${env_file} is outside the application directory`,
+        };
+      }
 
-      // Check if we've already run this tool (DATABASE_SCHEMA is only set by us)
-      if (existsSync(envPath)) {
-        const envContent = await readFile(envPath, "utf-8");
-        const env = dotenv.parse(envContent);
-        if (env.DATABASE_SCHEMA) {
+      const sqlPath = sql_file ? resolve(appDir, sql_file) : undefined;
+      if (sqlPath) {
+        const relPath = relative(appDir, sqlPath);
+        if (relPath.startsWith("..") || isAbsolute(relPath)) {
           return {
-            success: true,
-            message:
-              "DATABASE_SCHEMA already set in .env. Delete it and re-run if you need to regenerate.",
-            schema_name: app_name,
-            user_name: app_name,
+            success: false,
+            message: `SQL file ${sql_file} is outside the application directory`,
           };
         }
       }
 
+      // Check if we've already run this tool (DATABASE_SCHEMA is only set by us)
+      const existingEnv = await readEnvFile(envPath);
+      if (existingEnv.DATABASE_SCHEMA) {
+        return {
+          success: true,
+          message: `DATABASE_SCHEMA already set in ${env_file}. Delete it and re-run if you need to regenerate.`,
+          schema_name: app_name,
+          user_name: app_name,
+          env_file,
+        };
+      }
+
       // Get database connection string from Tiger
       let adminConnectionString: string;
       try {
-        const { stdout: serviceJson } = await execAsync(
-          `tiger service get ${service_id} --with-password -o json`,
-        );
-        const serviceDetails = JSON.parse(serviceJson) as {
-          connection_string?: string;
-        };
-
-        if (!serviceDetails.connection_string) {
-          return {
-            success: false,
-            message: "connection_string not found in service details",
-          };
-        }
-        adminConnectionString = serviceDetails.connection_string;
+        adminConnectionString = await getConnectionString(tiger, service_id);
       } catch (err) {
         const error = err as Error;
         return {
           success: false,
-          message: `Failed to get service details: ${error.message}`,
+          message: error.message,
         };
       }
 
       // Connect using postgres.js as admin
       const sql = postgres(adminConnectionString);
 
+      // Statements as applied, for the caller to review (password redacted)
+      const applied: string[] = [];
+      const run = async (statement: string, shown = statement) => {
+        await sql.unsafe(statement);
+        applied.push(`${shown};`);
+      };
+
       try {
         // Check if user already exists
         const existingUser = await sql`
@@ -134,7 +158,6 @@ export const setupAppSchemaFactory: ApiFactory<
         `;
 
         if (existingUser.length > 0) {
-          await sql.end();
           return {
             success: false,
             message: `User '${app_name}' already exists. Choose a different app name or delete the existing user.`,
@@ -143,25 +166,24 @@ export const setupAppSchemaFactory: ApiFactory<
 
         // Create new user
         const appPassword = generatePassword();
-        await sql.unsafe(
+        await run(
           `CREATE ROLE ${app_name} WITH LOGIN PASSWORD '${appPassword}'`,
+          `CREATE ROLE ${app_name} WITH LOGIN PASSWORD '********'`,
         );
 
         // Grant app role to tsdbadmin so admin can access app objects
-        await sql.unsafe(`GRANT ${app_name} TO tsdbadmin WITH INHERIT TRUE`);
+        await run(`GRANT ${app_name} TO tsdbadmin WITH INHERIT TRUE`);
 
         // Create app schema owned by the app user
-        await sql.unsafe(
+        await run(
           `CREATE SCHEMA IF NOT EXISTS ${app_name} AUTHORIZATION ${app_name}`,
         );
 
         // Revoke access to public schema
-        await sql.unsafe(`REVOKE ALL ON SCHEMA public FROM ${app_name}`);
+        await run(`REVOKE ALL ON SCHEMA public FROM ${app_name}`);
 
         // Set search_path for app user
-        await sql.unsafe(
-          `ALTER ROLE ${app_name} SET search_path TO ${app_name}`,
-        );
+        await run(`ALTER ROLE ${app_name} SET search_path TO ${app_name}`);
 
         // Append app schema to tsdbadmin's search_path
         const currentPath = await sql`
@@ -169,51 +191,57 @@ export const setupAppSchemaFactory: ApiFactory<
         `;
         const existingPath = currentPath[0]?.setting ?? "public";
         if (!existingPath.includes(app_name)) {
-          await sql.unsafe(
+          await run(
             `ALTER ROLE tsdbadmin SET search_path TO ${existingPath}, ${app_name}`,
           );
         }
 
-        await sql.end();
-
         // Build app connection string
-        const appDatabaseUrl = buildConnectionString(
+        const appDatabaseUrl = withCredentials(
           adminConnectionString,
           app_name,
           appPassword,
         );
 
-        // Write or update .env file
-        let envContent = "";
-        if (existsSync(envPath)) {
-          envContent = await readFile(envPath, "utf-8");
-        }
-
-        // Update or add DATABASE_URL and DATABASE_SCHEMA
-        const env = dotenv.parse(envContent);
-        env.DATABASE_URL = appDatabaseUrl;
-        env.DATABASE_SCHEMA = app_name;
-
-        // Rebuild .env content
-        const newEnvContent = Object.entries(env)
-          .map(([key, value]) => `${key}="${value}"`)
-          .join("\n");
-
-        await writeFile(envPath, `${newEnvContent}\n`);
+        // Update or add DATABASE_URL and DATABASE_SCHEMA in the env file
+        await updateEnvFile(envPath, {
+          DATABASE_URL: appDatabaseUrl,
+          DATABASE_SCHEMA: app_name,
+        });
       } catch (err) {
-        await sql.end();
         const error = err as Error;
         return {
           success: false,
           message: `Failed to set up app schema: ${error.message}`,
         };
+      } finally {
+        await sql.end();
+      }
+
+      // The schema and env file are in place, so a failure here is only
+      // reported in the message
+      let sqlFileNote = "";
+      let writtenSqlFile: string | undefined;
+      if (sqlPath) {
+        try {
+          await mkdir(dirname(sqlPath), { recursive: true });
+          await writeFile(sqlPath, `${applied.join("\n")}\n`);
+          sqlFileNote = ` Applied SQL written to ${sql_file}.`;
+          writtenSqlFile = sql_file;
+        } catch (err) {
+          const error = err as Error;
+          sqlFileNote = ` Couldn't write the applied SQL to ${sql_file} (${error.message}); it is still in the sql field.`;
+        }
       }
 
       return {
         success: true,
-        message: `Created schema '${app_name}' and user '${app_name}'. DATABASE_URL and DATABASE_SCHEMA written to .env.`,
+        message: `Created schema '${app_name}' and user '${app_name}'. DATABASE_URL and DATABASE_SCHEMA written to ${env_file}.${sqlFileNote}`,
         schema_name: app_name,
         user_name: app_name,
+        env_file,
+        sql: applied.join("\n"),
+        ...(writtenSqlFile ? { sql_file: writtenSqlFile } : {}),
       };
     },
   };
@@ -1,16 +1,14 @@
-import { exec } from "node:child_process";
 import { existsSync } from "node:fs";
 import { writeFile } from "node:fs/promises";
 import { join, resolve } from "node:path";
-import { promisify } from "node:util";
 import type { ApiFactory } from "@tigerdata/mcp-boilerplate";
 import postgres from "postgres";
 import { z } from "zod";
+import { withCredentials } from "../../lib/connectionString.js";
 import { writeTestingTemplates } from "../../lib/templates.js";
+import { getConnectionString } from "../../lib/tiger.js";
 import type { ServerContext } from "../../types.js";
 
-const execAsync = promisify(exec);
-
 const inputSchema = {
   application_directory: z
     .string()
@@ -60,22 +58,11 @@ function generatePassword(length = 24): string {
   return password;
 }
 
-function buildTestConnectionString(
-  originalUrl: string,
-  testUser: string,
-  testPassword: string,
-): string {
-  const parsed = new URL(originalUrl);
-  parsed.username = testUser;
-  parsed.password = encodeURIComponent(testPassword);
-  return parsed.toString();
-}
-
 export const setupTestingFactory: ApiFactory<
   ServerContext,
   typeof inputSchema,
   typeof outputSchema
-> = () => {
+> = ({ tiger }) => {
   return {
     name: "setup_testing",
     config: {
@@ -107,25 +94,12 @@ export const setupTestingFactory: ApiFactory<
       // Get database connection string from Tiger
       let adminConnectionString: string;
       try {
-        const { stdout: serviceJson } = await execAsync(
-          `tiger service get ${service_id} --with-password -o json`,
-        );
-        const serviceDetails = JSON.parse(serviceJson) as {
-          connection_string?: string;
-        };
-
-        if (!serviceDetails.connection_string) {
-          return {
-            success: false,
-            message: "connection_string not found in service details",
-          };
-        }
-        adminConnectionString = serviceDetails.connection_string;
+        adminConnectionString = await getConnectionString(tiger, service_id);
       } catch (err) {
         const error = err as Error;
         return {
           success: false,
-          message: `Failed to get service details: ${error.message}`,
+          message: error.message,
         };
       }
 
@@ -186,7 +160,7 @@ export const setupTestingFactory: ApiFactory<
         await sql.end();
 
         // Build test connection string and write to .env.test.local
-        const testDatabaseUrl = buildTestConnectionString(
+        const testDatabaseUrl = withCredentials(
           adminConnectionString,
           test_user,
           testPassword,
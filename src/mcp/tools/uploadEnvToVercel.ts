@@ -1,14 +1,15 @@
-import { spawn } from "node:child_process";
 import { existsSync, readFileSync } from "node:fs";
 import { resolve } from "node:path";
 import type { ApiFactory } from "@tigerdata/mcp-boilerplate";
 import * as dotenv from "dotenv";
 import { z } from "zod";
+import {
+  pushVercelEnv,
+  type VercelEnvironment,
+  vercelEnvironments,
+} from "../../lib/platformEnv.js";
 import type { ServerContext } from "../../types.js";
 
-const vercelEnvironments = ["production", "preview", "development"] as const;
-type VercelEnvironment = (typeof vercelEnvironments)[number];
-
 const inputSchema = {
   application_directory: z
     .string()
@@ -44,7 +45,7 @@ const outputSchema = {
     .describe("List of variable names skipped because they had empty values"),
 } as const;
 
-type FailedVar = {
+export type FailedVar = {
   name: string;
   error: string;
 };
@@ -87,67 +88,29 @@ function readEnvFile(appDir: string, envFilePath: string): ParsedEnvResult {
   return { vars, skippedEmpty };
 }
 
-function runVercelEnvAddSingle(
-  appDir: string,
-  name: string,
-  value: string,
-  vercelEnv: VercelEnvironment,
-): Promise<void> {
-  return new Promise((resolve, reject) => {
-    const args = [
-      "vercel",
-      "env",
-      "add",
-      name,
-      vercelEnv,
-      "--cwd",
-      appDir,
-      "--sensitive",
-      "--force",
-    ];
-
-    const child = spawn("npx", args, {
-      cwd: appDir,
-      stdio: ["pipe", "pipe", "pipe"],
-      env: {
-        ...process.env,
-        VERCEL_TELEMETRY_DISABLED: "1",
-      },
-    });
-
-    let stderr = "";
-    child.stderr.on("data", (data: Buffer) => {
-      stderr += data.toString();
-    });
-
-    child.on("error", (err) => {
-      reject(err);
-    });
-
-    child.on("exit", (code) => {
-      if (code === 0) {
-        resolve();
-      } else {
-        const errorMsg = stderr.trim() || `exit code ${code ?? "unknown"}`;
-        reject(new Error(errorMsg));
-      }
-    });
-
-    // Send the secret through stdin so it never appears on the command line
-    child.stdin.write(value);
-    child.stdin.end();
-  });
-}
-
-async function runVercelEnvAdd(
+/**
+ * Add each variable to the given Vercel environments, one at a time so a
+ * failure doesn't stop the rest. Also used by push_env.
+ */
+export async function uploadVercelVars(
   appDir: string,
-  name: string,
-  value: string,
-  environments: VercelEnvironment[],
-): Promise<void> {
-  for (const env of environments) {
-    await runVercelEnvAddSingle(appDir, name, value, env);
+  vars: Record<string, string>,
+  environments: readonly VercelEnvironment[],
+): Promise<{ uploaded: string[]; failed: FailedVar[] }> {
+  const uploaded: string[] = [];
+  const failed: FailedVar[] = [];
+
+  for (const [name, value] of Object.entries(vars)) {
+    try {
+      await pushVercelEnv(appDir, name, value, environments);
+      uploaded.push(name);
+    } catch (err) {
+      const error = err as Error;
+      failed.push({ name, error: error.message });
+    }
   }
+
+  return { uploaded, failed };
 }
 
 export const uploadEnvToVercelFactory: ApiFactory<
@@ -193,18 +156,11 @@ export const uploadEnvToVercelFactory: ApiFactory<
         };
       }
 
-      const uploaded: string[] = [];
-      const failed: FailedVar[] = [];
-
-      for (const [name, value] of Object.entries(envVars)) {
-        try {
-          await runVercelEnvAdd(appDir, name, value, environments);
-          uploaded.push(name);
-        } catch (err) {
-          const error = err as Error;
-          failed.push({ name, error: error.message });
-        }
-      }
+      const { uploaded, failed } = await uploadVercelVars(
+        appDir,
+        envVars,
+        environments,
+      );
 
       const skipped_empty = skippedEmpty.length > 0 ? skippedEmpty : undefined;
 
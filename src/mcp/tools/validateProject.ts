@@ -0,0 +1,227 @@
+import { existsSync } from "node:fs";
+import { readFile } from "node:fs/promises";
+import { createRequire } from "node:module";
+import { join, relative, resolve } from "node:path";
+import type { ApiFactory } from "@tigerdata/mcp-boilerplate";
+import { z } from "zod";
+import { runCommand } from "../../lib/exec.js";
+import type { ServerContext } from "../../types.js";
+
+const inputSchema = {
+  application_directory: z
+    .string()
+    .default(".")
+    .describe("Path to the application directory"),
+  build: z
+    .boolean()
+    .default(false)
+    .describe(
+      "Run the full production build (npm run build) instead of only type-checking",
+    ),
+} as const;
+
+const diagnosticSchema = z.object({
+  file: z.string().describe("Source file, relative to the app directory"),
+  line: z.number().describe("1-based line number"),
+  column: z.number().describe("1-based column number"),
+  code: z.string().optional().describe("Compiler error code, e.g. TS2322"),
+  message: z.string().describe("Diagnostic message"),
+});
+
+const outputSchema = {
+  success: z.boolean().describe("Whether the project type-checked or built"),
+  message: z.string().describe("Status message"),
+  command: z.string().optional().describe("The command that was run"),
+  diagnostics: z
+    .array(diagnosticSchema)
+    .optional()
+    .describe("Errors reported by the compiler, up to the first 50"),
+  output: z
+    .string()
+    .optional()
+    .describe(
+      "Tail of the command output when it failed without parseable diagnostics",
+    ),
+  exit_code: z.number().optional().describe("Exit code of the command"),
+} as const;
+
+type Diagnostic = z.infer<typeof diagnosticSchema>;
+
+type OutputSchema = {
+  success: boolean;
+  message: string;
+  command?: string;
+  diagnostics?: Diagnostic[];
+  output?: string;
+  exit_code?: number;
+};
+
+const maxDiagnostics = 50;
+const maxOutputChars = 4000;
+
+// tsc --pretty false: src/app/page.tsx(12,5): error TS2322: Type ...
+const tscPattern = /^(.+?)\((\d+),(\d+)\): error (TS\d+): (.*)$/;
+// next build: ./src/app/page.tsx:12:5 followed by "Type error: ..."
+const nextLocationPattern = /^(\.{0,2}\/?[^\s:]+\.[cm]?[jt]sx?):(\d+):(\d+)$/;
+
+/**
+ * Parse tsc and next build output into structured diagnostics
+ */
+export function parseDiagnostics(output: string, appDir: string): Diagnostic[] {
+  const diagnostics: Diagnostic[] = [];
+  const lines = output.split(/\r?\n/);
+  const toRelative = (file: string) => relative(appDir, resolve(appDir, file));
+
+  for (let i = 0; i < lines.length; i++) {
+    const line = lines[i]?.trim() ?? "";
+
+    const tsc = tscPattern.exec(line);
+    if (tsc) {
+      const [, file = "", row = "0", col = "0", code = "", message = ""] = tsc;
+      diagnostics.push({
+        file: toRelative(file),
+        line: Number(row),
+        column: Number(col),
+        code,
+        message,
+      });
+      continue;
+    }
+
+    const next = nextLocationPattern.exec(line);
+    if (next) {
+      const [, file = "", row = "0", col = "0"] = next;
+      const message = (lines[i + 1] ?? "")
+        .trim()
+        .replace(/^Type error:\s*/, "");
+      diagnostics.push({
+        file: toRelative(file),
+        line: Number(row),
+        column: Number(col),
+        message,
+      });
+      i++;
+    }
+  }
+
+  return diagnostics;
+}
+
+/**
+ * Whether the app can resolve the typescript package, including one hoisted
+ * to a workspace root
+ */
+export function hasTypeScript(appDir: string): boolean {
+  try {
+    createRequire(join(appDir, "package.json")).resolve(
+      "typescript/package.json",
+    );
+    return true;
+  } catch {
+    return false;
+  }
+}
+
+async function hasBuildScript(appDir: string): Promise<boolean> {
+  const pkg = JSON.parse(
+    await readFile(join(appDir, "package.json"), "utf-8"),
+  ) as { scripts?: Record<string, string> };
+  return Boolean(pkg.scripts?.build);
+}
+
+export const validateProjectFactory: ApiFactory<
+  ServerContext,
+  typeof inputSchema,
+  typeof outputSchema
+> = () => {
+  return {
+    name: "validate_project",
+    config: {
+      title: "Validate Project",
+      description:
+        "✅ Type-check the app with tsc --noEmit (or run the production build with build: true) and return structured diagnostics with file, line, and message. Use after making edits as a fast correctness check instead of starting the dev server.",
+      inputSchema,
+      outputSchema,
+    },
+    fn: async ({ application_directory, build }): Promise<OutputSchema> => {
+      const appDir = resolve(process.cwd(), application_directory);
+
+      if (!existsSync(join(appDir, "package.json"))) {
+        return {
+          success: false,
+          message: `No package.json found in ${appDir}`,
+        };
+      }
+
+      let file: string;
+      let args: string[];
+      if (build) {
+        try {
+          if (!(await hasBuildScript(appDir))) {
+            return {
+              success: false,
+              message: `package.json in ${appDir} has no "build" script`,
+            };
+          }
+        } catch (err) {
+          const error = err as Error;
+          return {
+            success: false,
+            message: `Failed to read package.json: ${error.message}`,
+          };
+        }
+        file = "npm";
+        args = ["run", "build"];
+      } else {
+        if (!existsSync(join(appDir, "tsconfig.json"))) {
+          return {
+            success: false,
+            message: `No tsconfig.json found in ${appDir}. Use build: true to run the production build instead.`,
+          };
+        }
+        // A bare `npx tsc` would download the unrelated "tsc" package
+        if (!hasTypeScript(appDir)) {
+          return {
+            success: false,
+            message: `TypeScript is not installed in ${appDir}. Run npm install, or add typescript as a devDependency.`,
+          };
+        }
+        file = "npx";
+        args = ["--no-install", "tsc", "--noEmit", "--pretty", "false"];
+      }
+
+      const command = [file, ...args].join(" ");
+      const result = await runCommand(file, args, {
+        cwd: appDir,
+        maxBuffer: 10 * 1024 * 1024,
+      });
+
+      if (result.exitCode === 0) {
+        return {
+          success: true,
+          message: build ? "Build succeeded" : "Type check passed",
+          command,
+          diagnostics: [],
+          exit_code: 0,
+        };
+      }
+
+      const output = `${result.stdout}\n${result.stderr}`;
+      const diagnostics = parseDiagnostics(output, appDir);
+
+      return {
+        success: false,
+        message:
+          diagnostics.length > 0
+            ? `${build ? "Build" : "Type check"} failed with ${diagnostics.length} error(s)`
+            : `${build ? "Build" : "Type check"} failed with exit code ${result.exitCode}`,
+        command,
+        diagnostics: diagnostics.slice(0, maxDiagnostics),
+        ...(diagnostics.length === 0
+          ? { output: output.trim().slice(-maxOutputChars) }
+          : {}),
+        exit_code: result.exitCode,
+      };
+    },
+  };
+};
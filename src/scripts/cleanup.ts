@@ -19,7 +19,7 @@ async function cleanup(): Promise<void> {
 
   console.log("0perator: Cleanup complete");
   console.log(
-    "0perator: Please manually remove '0perator' from your IDE's MCP configuration",
+    "0perator: Run 'npx 0perator uninstall' to remove '0perator' from your IDE's MCP configuration",
   );
 }
 
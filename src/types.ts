@@ -1,4 +1,6 @@
+import type { TigerRunner } from "./lib/tiger.js";
+
 export interface ServerContext extends Record<string, unknown> {
-  // No database connection needed for 0perator
-  // Context can be extended later if needed
+  // Runs the tiger CLI; replaced with a fake in tests
+  tiger: TigerRunner;
 }